@@ -1,15 +1,25 @@
 package cleaner
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
+	"sync"
+	"time"
 
+	"github.com/raoulg/venvcleaner/journal"
 	"github.com/raoulg/venvcleaner/model"
+	"github.com/raoulg/venvcleaner/scanner"
 )
 
-// DetectRemovalTool checks if 'rip' is available, otherwise falls back to 'rm' or 'native'
+// RemovalTools lists the tool names accepted by DeleteVenv
+var RemovalTools = []string{"native", "rip", "trash", "rm"}
+
+// DetectRemovalTool checks if 'rip' is available, otherwise uses the built-in
+// freedesktop.org trash where that is the desktop convention, and falls back to 'rm' or 'native'
 func DetectRemovalTool() string {
 	// On Windows, use native Go removal
 	if runtime.GOOS == "windows" {
@@ -21,9 +31,36 @@ func DetectRemovalTool() string {
 	if err == nil {
 		return "rip"
 	}
+
+	// macOS has its own Trash, every other Unix follows the XDG spec
+	if runtime.GOOS != "darwin" {
+		return "trash"
+	}
+	return "rm"
+}
+
+// PermanentRemovalTool returns the tool that frees space right away on this
+// system, for unattended runs: rm, or native on Windows
+func PermanentRemovalTool() string {
+	if runtime.GOOS == "windows" {
+		return "native"
+	}
 	return "rm"
 }
 
+// RecoveryPlace returns where tool keeps what it removes, such as "the trash",
+// or "" if it deletes outright. Removing with such a tool frees no space
+// until that place is emptied.
+func RecoveryPlace(tool string) string {
+	switch tool {
+	case "trash":
+		return "the trash"
+	case "rip":
+		return "rip's graveyard"
+	}
+	return ""
+}
+
 // DeleteVenv removes a .venv directory using the specified tool
 func DeleteVenv(venvPath string, tool string) error {
 	switch tool {
@@ -44,6 +81,13 @@ func DeleteVenv(venvPath string, tool string) error {
 		}
 		return nil
 
+	case "trash":
+		// Built-in freedesktop.org trash, recoverable from any file manager
+		if _, err := MoveToTrash(venvPath); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", venvPath, err)
+		}
+		return nil
+
 	case "rm":
 		// rm requires -rf for recursive directory removal (Unix/Linux/macOS)
 		cmd := exec.Command("rm", "-rf", venvPath)
@@ -58,14 +102,39 @@ func DeleteVenv(venvPath string, tool string) error {
 	}
 }
 
-// DeleteSelected removes all selected .venv folders and sends progress updates
-func DeleteSelected(repos []model.VenvInfo, progressChan chan<- model.Progress) error {
-	tool := DetectRemovalTool()
+// Options configures a cleaning run
+type Options struct {
+	Tool   string // Removal tool passed to DeleteVenv; empty means DetectRemovalTool
+	DryRun bool   // Run the whole pipeline, including progress updates, without deleting anything
+	Jobs   int    // Maximum number of concurrent deletions; below 1 means 1
+}
+
+// RemovalTool returns the tool a run uses: Tool, or DetectRemovalTool if it is empty
+func (o Options) RemovalTool() string {
+	if o.Tool == "" {
+		return DetectRemovalTool()
+	}
+	return o.Tool
+}
+
+// ErrJournal marks errors writing the deletion journal; the deletions themselves went ahead
+var ErrJournal = errors.New("cannot write deletion journal")
+
+// DeleteSelected removes all selected .venv folders and artifacts and sends a progress update
+// for every item, carrying the error if that item could not be deleted. Failed
+// deletions do not stop the run; they are returned joined together.
+// Up to opts.Jobs items are deleted concurrently, but progress is always
+// reported in selection order with cumulative counts and sizes.
+// Cancelling ctx lets deletions already in progress finish; every item not
+// started yet is reported with an error matching ctx.Err() and left untouched.
+// Every attempt, except in a dry run, is recorded in the deletion journal.
+func DeleteSelected(ctx context.Context, repos []model.VenvInfo, progressChan chan<- model.Progress, opts Options) error {
+	tool := opts.RemovalTool()
 
 	// Filter only selected repos
 	var selected []model.VenvInfo
 	for _, repo := range repos {
-		if repo.Selected {
+		if repo.HasSelection() {
 			selected = append(selected, repo)
 		}
 	}
@@ -75,28 +144,186 @@ func DeleteSelected(repos []model.VenvInfo, progressChan chan<- model.Progress)
 		return nil
 	}
 
+	jobs := min(max(opts.Jobs, 1), len(selected))
+	limiter := newDeviceLimiter(jobs)
+
+	type result struct {
+		index int
+		left  model.VenvInfo // What is left of the selection, nothing selected once it is all gone
+		err   error
+	}
+	indexes := make(chan int)
+	results := make(chan result)
+
+	// Workers delete items as they become available
+	var wg sync.WaitGroup
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				repo := selected[i]
+
+				// Delete the .venv, unless we are only simulating or were cancelled
+				var left model.VenvInfo
+				err := ctx.Err()
+				if err != nil {
+					left = repo
+				} else if !opts.DryRun {
+					release := limiter.acquire(repo.Path())
+					left, err = deleteItem(repo, tool)
+					release()
+				}
+				results <- result{index: i, left: left, err: err}
+			}
+		}()
+	}
+
+	// Hand out items until cancelled, then report the rest as untouched
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(indexes)
+		for i := range selected {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				for ; i < len(selected); i++ {
+					results <- result{index: i, left: selected[i], err: ctx.Err()}
+				}
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
 	total := len(selected)
 	var totalSize int64
+	var errs []error
+	skipped := false
 
-	for i, repo := range selected {
-		// Delete the .venv
-		err := DeleteVenv(repo.VenvPath, tool)
-		if err != nil {
-			// Log error but continue with remaining deletions
-			fmt.Fprintf(os.Stderr, "Error deleting %s: %v\n", repo.VenvPath, err)
-			continue
-		}
+	// Hold back results that finish early so progress stays in selection order
+	pending := make(map[int]result)
+	next := 0
+	for r := range results {
+		pending[r.index] = r
 
-		totalSize += repo.Size
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			repo, err := selected[next], r.err
+			next++
 
-		// Send progress update
-		progressChan <- model.Progress{
-			Current: i + 1,
-			Total:   total,
-			Size:    totalSize,
+			if err != nil && errors.Is(err, ctx.Err()) {
+				skipped = true
+			} else if err != nil {
+				// Remember the error but continue with remaining deletions
+				errs = append(errs, err)
+			}
+
+			progress := model.Progress{
+				Current: next,
+				Total:   total,
+				Item:    repo,
+			}
+			if r.left.HasSelection() {
+				// Only what is left failed; the rest of the item went
+				progress.Item, progress.Err = r.left, err
+			}
+			totalSize += repo.SelectedSize() - r.left.SelectedSize()
+			progress.Size = totalSize
+
+			// Send progress update
+			progressChan <- progress
 		}
 	}
 
 	close(progressChan)
-	return nil
+	if skipped {
+		errs = append(errs, ctx.Err())
+	}
+	return errors.Join(errs...)
+}
+
+// deleteItem deletes what is selected of one item: its venv, its artifacts or
+// both. Every artifact directory is deleted with the same tool as the venv and
+// journaled on its own, so trashed artifacts can be restored too. It returns
+// the item narrowed to what could not be deleted, with nothing selected once
+// every part is gone, so a part that went is neither reported as failed nor
+// deleted again on a retry.
+func deleteItem(repo model.VenvInfo, tool string) (model.VenvInfo, error) {
+	left := repo
+	var errs []error
+	if repo.Selected {
+		err := deleteAndRecord(repo, tool)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		left.Selected = err != nil && !errors.Is(err, ErrJournal)
+	}
+	if repo.ArtifactsSelected {
+		left.Artifacts, left.ArtifactsSize = nil, 0
+		for _, artifact := range repo.Artifacts {
+			item := model.VenvInfo{
+				Kind:         model.KindArtifacts,
+				RepoPath:     repo.RepoPath,
+				VenvPath:     artifact.Path,
+				LastModified: artifact.LastModified,
+				Size:         artifact.Size,
+			}
+			err := deleteAndRecord(item, tool)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			if err != nil && !errors.Is(err, ErrJournal) {
+				left.Artifacts = append(left.Artifacts, artifact)
+				left.ArtifactsSize += artifact.Size
+			}
+		}
+		left.ArtifactsSelected = len(left.Artifacts) > 0
+	}
+	return left, errors.Join(errs...)
+}
+
+// deleteAndRecord deletes one venv and appends the attempt to the journal.
+// If only the journal write fails, the returned error wraps ErrJournal.
+func deleteAndRecord(repo model.VenvInfo, tool string) error {
+	// Read the version first, pyvenv.cfg is gone afterwards
+	cfg, _ := scanner.ReadPyvenvCfg(repo.VenvPath)
+
+	err := DeleteVenv(repo.VenvPath, tool)
+
+	// Conda keeps listing removed environments until they are unregistered.
+	// That is only cosmetic, so it does not fail the deletion.
+	if err == nil && repo.Kind == model.KindConda {
+		unregisterCondaEnv(repo.VenvPath)
+	}
+
+	entry := journal.Entry{
+		Time:          time.Now(),
+		User:          journal.CurrentUser(),
+		Tool:          tool,
+		Kind:          string(repo.Kind),
+		VenvPath:      repo.VenvPath,
+		RepoPath:      repo.RepoPath,
+		SizeBytes:     repo.Size,
+		PythonVersion: scanner.PythonVersion(cfg),
+		Outcome:       journal.OutcomeRemoved,
+	}
+	if err != nil {
+		entry.Outcome = journal.OutcomeFailed
+		entry.Error = err.Error()
+	}
+	if jerr := journal.Append(entry); jerr != nil && err == nil {
+		return fmt.Errorf("%w: %v", ErrJournal, jerr)
+	}
+
+	return err
 }
@@ -0,0 +1,190 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/raoulg/venvcleaner/model"
+)
+
+// newVenvs creates n selected venv folders of growing size, and keeps the
+// deletion journal out of the user's state directory
+func newVenvs(t *testing.T, n int) []model.VenvInfo {
+	t.Helper()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	root := t.TempDir()
+	var venvs []model.VenvInfo
+	for i := 0; i < n; i++ {
+		repo := filepath.Join(root, fmt.Sprintf("repo%d", i))
+		venv := filepath.Join(repo, ".venv")
+		if err := os.MkdirAll(venv, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		size := 100 * (i + 1)
+		if err := os.WriteFile(filepath.Join(venv, "lib"), make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		venvs = append(venvs, model.VenvInfo{
+			Kind:     model.KindVenv,
+			RepoPath: repo,
+			VenvPath: venv,
+			Size:     int64(size),
+			Selected: true,
+		})
+	}
+	return venvs
+}
+
+// deleteAll runs DeleteSelected and collects its progress updates
+func deleteAll(ctx context.Context, venvs []model.VenvInfo, opts Options) ([]model.Progress, error) {
+	progressChan := make(chan model.Progress)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- DeleteSelected(ctx, venvs, progressChan, opts)
+	}()
+
+	var updates []model.Progress
+	for p := range progressChan {
+		updates = append(updates, p)
+	}
+	return updates, <-errChan
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func TestDeleteSelectedReportsInOrder(t *testing.T) {
+	for _, jobs := range []int{0, 1, 4, 20} {
+		t.Run(fmt.Sprintf("%d jobs", jobs), func(t *testing.T) {
+			venvs := newVenvs(t, 10)
+			venvs[3].Selected = false // Left out entirely
+
+			updates, err := deleteAll(context.Background(), venvs, Options{Tool: "native", Jobs: jobs})
+			if err != nil {
+				t.Fatalf("DeleteSelected() error = %v", err)
+			}
+			if len(updates) != 9 {
+				t.Fatalf("got %d progress updates, want 9", len(updates))
+			}
+
+			var want []model.VenvInfo
+			for _, venv := range venvs {
+				if venv.Selected {
+					want = append(want, venv)
+				}
+			}
+			var size int64
+			for i, p := range updates {
+				size += want[i].Size
+				if p.Item.VenvPath != want[i].VenvPath || p.Current != i+1 || p.Total != 9 || p.Size != size || p.Err != nil {
+					t.Errorf("update %d = {%s %d/%d %d %v}, want {%s %d/9 %d <nil>}",
+						i, p.Item.VenvPath, p.Current, p.Total, p.Size, p.Err, want[i].VenvPath, i+1, size)
+				}
+			}
+
+			for _, venv := range venvs {
+				if exists(venv.VenvPath) == venv.Selected {
+					t.Errorf("%s exists = %v, selected = %v", venv.VenvPath, exists(venv.VenvPath), venv.Selected)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteSelectedReportsFailures(t *testing.T) {
+	venvs := newVenvs(t, 3)
+	if err := os.RemoveAll(venvs[1].VenvPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// Trashing a folder that is gone fails, the others still go
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	updates, err := deleteAll(context.Background(), venvs, Options{Tool: "trash", Jobs: 2})
+	if err == nil {
+		t.Fatal("DeleteSelected() error = nil, want the failure")
+	}
+	if len(updates) != 3 {
+		t.Fatalf("got %d progress updates, want 3", len(updates))
+	}
+	if updates[1].Err == nil || updates[0].Err != nil || updates[2].Err != nil {
+		t.Errorf("errors = %v, %v, %v; want only the second", updates[0].Err, updates[1].Err, updates[2].Err)
+	}
+	if want := venvs[0].Size + venvs[2].Size; updates[2].Size != want {
+		t.Errorf("size = %d, want %d without the failure", updates[2].Size, want)
+	}
+}
+
+func TestDeleteSelectedCancelledBeforeStart(t *testing.T) {
+	venvs := newVenvs(t, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	updates, err := deleteAll(ctx, venvs, Options{Tool: "native", Jobs: 2})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("DeleteSelected() error = %v, want %v", err, context.Canceled)
+	}
+	if len(updates) != len(venvs) {
+		t.Fatalf("got %d progress updates, want %d", len(updates), len(venvs))
+	}
+	for i, p := range updates {
+		if !errors.Is(p.Err, context.Canceled) || p.Size != 0 || p.Item.VenvPath != venvs[i].VenvPath {
+			t.Errorf("update %d = {%s %d %v}, want {%s 0 %v}", i, p.Item.VenvPath, p.Size, p.Err, venvs[i].VenvPath, context.Canceled)
+		}
+	}
+	for _, venv := range venvs {
+		if !exists(venv.VenvPath) {
+			t.Errorf("%s was deleted after cancelling", venv.VenvPath)
+		}
+	}
+}
+
+func TestDeleteSelectedCancelledMidRun(t *testing.T) {
+	venvs := newVenvs(t, 6)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	progressChan := make(chan model.Progress)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- DeleteSelected(ctx, venvs, progressChan, Options{Tool: "native", Jobs: 1})
+	}()
+
+	// Cancel once the first item is reported. By then the worker can have
+	// finished the second and started the third, which is let finish; the
+	// fourth cannot be handed out before the second is reported, after this.
+	var updates []model.Progress
+	for p := range progressChan {
+		if len(updates) == 0 {
+			cancel()
+		}
+		updates = append(updates, p)
+	}
+	if err := <-errChan; !errors.Is(err, context.Canceled) {
+		t.Fatalf("DeleteSelected() error = %v, want %v", err, context.Canceled)
+	}
+	if len(updates) != len(venvs) {
+		t.Fatalf("got %d progress updates, want %d", len(updates), len(venvs))
+	}
+
+	for i, p := range updates {
+		skipped := errors.Is(p.Err, context.Canceled)
+		switch {
+		case p.Err != nil && !skipped:
+			t.Errorf("update %d failed: %v", i, p.Err)
+		case i == 0 && skipped:
+			t.Errorf("update %d skipped, but it was done before cancelling", i)
+		case i >= 3 && !skipped:
+			t.Errorf("update %d not skipped, but it was started after cancelling", i)
+		}
+		if exists(venvs[i].VenvPath) != skipped {
+			t.Errorf("%s exists = %v, skipped = %v", venvs[i].VenvPath, exists(venvs[i].VenvPath), skipped)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/raoulg/venvcleaner/scanner"
+)
+
+// condaRegistryMu serializes rewrites of environments.txt by parallel deletions
+var condaRegistryMu sync.Mutex
+
+// unregisterCondaEnv removes a deleted environment from conda's
+// environments.txt, as `conda env remove` does
+func unregisterCondaEnv(prefix string) error {
+	registry, err := scanner.CondaEnvironmentsFile()
+	if err != nil {
+		return err
+	}
+
+	condaRegistryMu.Lock()
+	defer condaRegistryMu.Unlock()
+
+	data, err := os.ReadFile(registry)
+	if err != nil {
+		return err
+	}
+
+	var kept []string
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" && filepath.Clean(trimmed) == filepath.Clean(prefix) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	// Replace the file in one step so conda never reads half of it
+	tmp := registry + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(kept, "")), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, registry)
+}
@@ -0,0 +1,74 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnregisterCondaEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry string
+		want     string
+	}{
+		{
+			name:     "only the deleted env",
+			registry: "/opt/conda\n/home/me/envs/old\n/home/me/envs/older\n",
+			want:     "/opt/conda\n/home/me/envs/older\n",
+		},
+		{
+			name:     "listed twice, with a trailing slash and spaces",
+			registry: "/home/me/envs/old/\n/opt/conda\n  /home/me/envs/old  \n",
+			want:     "/opt/conda\n",
+		},
+		{
+			name:     "last line without a newline",
+			registry: "/opt/conda\n/home/me/envs/old",
+			want:     "/opt/conda\n",
+		},
+		{
+			name:     "not listed",
+			registry: "/opt/conda\n\n/home/me/envs/other\n",
+			want:     "/opt/conda\n\n/home/me/envs/other\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("USERPROFILE", home)
+			registry := filepath.Join(home, ".conda", "environments.txt")
+			if err := os.MkdirAll(filepath.Dir(registry), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(registry, []byte(tt.registry), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := unregisterCondaEnv("/home/me/envs/old"); err != nil {
+				t.Fatalf("unregisterCondaEnv() error = %v", err)
+			}
+			got, err := os.ReadFile(registry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("environments.txt = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnregisterCondaEnvWithoutRegistry(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	if err := unregisterCondaEnv("/home/me/envs/old"); err == nil {
+		t.Error("unregisterCondaEnv() without environments.txt succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(home, ".conda")); !os.IsNotExist(err) {
+		t.Errorf("unregisterCondaEnv() created a registry: %v", err)
+	}
+}
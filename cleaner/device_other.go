@@ -0,0 +1,10 @@
+//go:build !unix
+
+package cleaner
+
+import "errors"
+
+// deviceOf is not supported on this platform, so trashing is not either
+func deviceOf(path string) (uint64, error) {
+	return 0, errors.New("device lookup is not supported on this platform")
+}
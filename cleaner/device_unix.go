@@ -0,0 +1,22 @@
+//go:build unix
+
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// deviceOf returns the ID of the device holding path
+func deviceOf(path string) (uint64, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("cannot determine device of %s", path)
+	}
+	return uint64(stat.Dev), nil
+}
@@ -0,0 +1,43 @@
+package cleaner
+
+import "sync"
+
+// deviceLimiter bounds how many deletions run at once on a single device.
+// Concurrent deletions help on SSDs and network storage, but on a spinning
+// disk they only make the heads seek back and forth, so those get one at a time.
+type deviceLimiter struct {
+	mu        sync.Mutex
+	perDevice int
+	sems      map[uint64]chan struct{}
+}
+
+func newDeviceLimiter(perDevice int) *deviceLimiter {
+	return &deviceLimiter{
+		perDevice: perDevice,
+		sems:      make(map[uint64]chan struct{}),
+	}
+}
+
+// acquire blocks until a deletion of path may start and returns the function that ends it
+func (l *deviceLimiter) acquire(path string) (release func()) {
+	dev, err := deviceOf(path)
+	if err != nil {
+		// Unknown device, nothing to coordinate with
+		return func() {}
+	}
+
+	l.mu.Lock()
+	sem, ok := l.sems[dev]
+	if !ok {
+		limit := l.perDevice
+		if isRotational(dev) {
+			limit = 1
+		}
+		sem = make(chan struct{}, limit)
+		l.sems[dev] = sem
+	}
+	l.mu.Unlock()
+
+	sem <- struct{}{}
+	return func() { <-sem }
+}
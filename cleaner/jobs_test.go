@@ -0,0 +1,58 @@
+package cleaner
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDeviceLimiter(t *testing.T) {
+	dir := t.TempDir()
+	dev, err := deviceOf(dir)
+	if err != nil {
+		t.Skipf("device of %s unknown: %v", dir, err)
+	}
+	limit := 3
+	if isRotational(dev) {
+		limit = 1
+	}
+	l := newDeviceLimiter(3)
+
+	var releases []func()
+	for i := 0; i < limit; i++ {
+		releases = append(releases, l.acquire(dir))
+	}
+
+	// The device is full, so the next deletion waits for one to end
+	acquired := make(chan func())
+	go func() { acquired <- l.acquire(dir) }()
+	select {
+	case <-acquired:
+		t.Fatalf("acquired more than %d deletions on one device", limit)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	releases[0]()
+	select {
+	case release := <-acquired:
+		release()
+	case <-time.After(5 * time.Second):
+		t.Fatal("deletion still waiting after another one ended")
+	}
+
+	// A path whose device is unknown is never held up
+	done := make(chan struct{})
+	go func() {
+		l.acquire(filepath.Join(dir, "missing"))()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deletion on an unknown device waited")
+	}
+
+	for _, release := range releases[1:] {
+		release()
+	}
+}
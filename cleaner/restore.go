@@ -0,0 +1,215 @@
+package cleaner
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raoulg/venvcleaner/model"
+	"github.com/raoulg/venvcleaner/scanner"
+)
+
+// TrashedItem is a virtual environment, or another folder venvcleaner can
+// delete, sitting in a freedesktop.org trash directory
+type TrashedItem struct {
+	Name         string        // Name inside the trash's files/ directory
+	Kind         model.EnvKind // Kind of folder, guessed from its contents and original name
+	TrashDir     string        // Trash directory holding the item
+	OriginalPath string        // Absolute path the item was deleted from
+	DeletedAt    time.Time     // DeletionDate from the .trashinfo file
+	Size         int64         // Total size in bytes
+}
+
+// TrashedPath returns where the item currently lives
+func (t TrashedItem) TrashedPath() string {
+	return filepath.Join(t.TrashDir, "files", t.Name)
+}
+
+// infoPath returns the item's .trashinfo file
+func (t TrashedItem) infoPath() string {
+	return filepath.Join(t.TrashDir, "info", t.Name+".trashinfo")
+}
+
+// ListTrashedVenvs returns every virtual environment, dependency folder and
+// artifact found in the home trash and the per-mount trashes, most recently
+// deleted first
+func ListTrashedVenvs() ([]TrashedItem, error) {
+	dirs, err := trashDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []TrashedItem
+	for _, dir := range dirs {
+		infos, err := filepath.Glob(filepath.Join(dir.path, "info", "*.trashinfo"))
+		if err != nil {
+			continue
+		}
+		for _, infoPath := range infos {
+			item, err := readTrashInfo(dir, infoPath)
+			if err != nil {
+				continue
+			}
+			var ok bool
+			if item.Kind, ok = trashedKind(item); !ok {
+				continue
+			}
+			item.Size, _ = scanner.GetVenvSize(item.TrashedPath())
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+
+	return items, nil
+}
+
+// RestoreTrashed moves a trashed item back to its original location. It
+// refuses to overwrite anything that exists there now.
+func RestoreTrashed(item TrashedItem) error {
+	if _, err := os.Lstat(item.OriginalPath); err == nil {
+		return fmt.Errorf("cannot restore %s: something already exists there", item.OriginalPath)
+	}
+
+	parent := filepath.Dir(item.OriginalPath)
+	if info, err := os.Stat(parent); err != nil || !info.IsDir() {
+		return fmt.Errorf("cannot restore %s: %s no longer exists", item.OriginalPath, parent)
+	}
+
+	if err := os.Rename(item.TrashedPath(), item.OriginalPath); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", item.OriginalPath, err)
+	}
+
+	// The item is back, a stale .trashinfo would only confuse file managers
+	os.Remove(item.infoPath())
+	return nil
+}
+
+// trashDir is one trash directory; topdir is the mount it belongs to, empty for the home trash
+type trashDir struct {
+	path   string
+	topdir string
+}
+
+// trashDirs returns the home trash and every per-mount trash of the current user that exists
+func trashDirs() ([]trashDir, error) {
+	homeTrash, err := HomeTrashDir()
+	if err != nil {
+		return nil, err
+	}
+	dirs := []trashDir{{path: homeTrash}}
+
+	uid := strconv.Itoa(os.Getuid())
+	for _, topdir := range mountPoints() {
+		for _, path := range []string{
+			filepath.Join(topdir, ".Trash", uid),
+			filepath.Join(topdir, ".Trash-"+uid),
+		} {
+			if info, err := os.Stat(path); err == nil && info.IsDir() && path != homeTrash {
+				dirs = append(dirs, trashDir{path: path, topdir: topdir})
+			}
+		}
+	}
+
+	return dirs, nil
+}
+
+// mountPoints lists mounted filesystems. Only Linux exposes them as a file;
+// elsewhere only the home trash is searched.
+func mountPoints() []string {
+	f, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var mounts []string
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		fields := strings.Fields(lines.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		mounts = append(mounts, unescapeMountPath(fields[1]))
+	}
+	return mounts
+}
+
+// unescapeMountPath decodes the octal escapes (\040 for space) used in /proc/self/mounts
+func unescapeMountPath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			if n, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(path[i])
+	}
+	return b.String()
+}
+
+// readTrashInfo parses a .trashinfo file
+func readTrashInfo(dir trashDir, infoPath string) (TrashedItem, error) {
+	item := TrashedItem{
+		Name:     strings.TrimSuffix(filepath.Base(infoPath), ".trashinfo"),
+		TrashDir: dir.path,
+	}
+
+	f, err := os.Open(infoPath)
+	if err != nil {
+		return item, err
+	}
+	defer f.Close()
+
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		key, value, ok := strings.Cut(lines.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Path":
+			path, err := url.PathUnescape(value)
+			if err != nil {
+				return item, err
+			}
+			path = filepath.FromSlash(path)
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir.topdir, path)
+			}
+			item.OriginalPath = path
+		case "DeletionDate":
+			item.DeletedAt, _ = time.ParseInLocation(trashInfoTimeFormat, value, time.Local)
+		}
+	}
+
+	if item.OriginalPath == "" {
+		return item, fmt.Errorf("%s has no Path", infoPath)
+	}
+	return item, lines.Err()
+}
+
+// trashedKind returns the kind of a trashed directory: a venv if it holds a
+// pyvenv.cfg, and otherwise whatever its original name suggests. ok is false
+// for anything else, which venvcleaner did not put there.
+func trashedKind(item TrashedItem) (kind model.EnvKind, ok bool) {
+	info, err := os.Stat(item.TrashedPath())
+	if err != nil || !info.IsDir() {
+		return "", false
+	}
+	if _, err := os.Stat(filepath.Join(item.TrashedPath(), "pyvenv.cfg")); err == nil {
+		return model.KindVenv, true
+	}
+	return scanner.KindOfFolder(filepath.Base(item.OriginalPath))
+}
@@ -0,0 +1,162 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/raoulg/venvcleaner/model"
+)
+
+func TestReadTrashInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    string
+		topdir  string
+		want    string
+		deleted time.Time
+		wantErr bool
+	}{
+		{
+			name:    "absolute path",
+			info:    "[Trash Info]\nPath=/home/me/src/app/.venv\nDeletionDate=2026-03-01T14:05:09\n",
+			want:    "/home/me/src/app/.venv",
+			deleted: time.Date(2026, 3, 1, 14, 5, 9, 0, time.Local),
+		},
+		{
+			name:    "escaped path",
+			info:    "[Trash Info]\nPath=/home/me/my%20src/app%25/node_modules\nDeletionDate=2026-03-01T14:05:09\n",
+			want:    "/home/me/my src/app%/node_modules",
+			deleted: time.Date(2026, 3, 1, 14, 5, 9, 0, time.Local),
+		},
+		{
+			name:    "relative to the mount of a per-mount trash",
+			info:    "[Trash Info]\nPath=src/app/.venv\nDeletionDate=2026-03-01T14:05:09\n",
+			topdir:  "/mnt/data",
+			want:    "/mnt/data/src/app/.venv",
+			deleted: time.Date(2026, 3, 1, 14, 5, 9, 0, time.Local),
+		},
+		{
+			name: "unreadable date",
+			info: "[Trash Info]\nPath=/home/me/app/.venv\nDeletionDate=yesterday\n",
+			want: "/home/me/app/.venv",
+		},
+		{
+			name:    "no path",
+			info:    "[Trash Info]\nDeletionDate=2026-03-01T14:05:09\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad escape",
+			info:    "[Trash Info]\nPath=/home/me/%zz\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := trashDir{path: t.TempDir(), topdir: tt.topdir}
+			infoPath := filepath.Join(dir.path, ".venv.trashinfo")
+			if err := os.WriteFile(infoPath, []byte(tt.info), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			item, err := readTrashInfo(dir, infoPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readTrashInfo error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if item.Name != ".venv" {
+				t.Errorf("Name = %q, want %q", item.Name, ".venv")
+			}
+			if item.OriginalPath != tt.want {
+				t.Errorf("OriginalPath = %q, want %q", item.OriginalPath, tt.want)
+			}
+			if !item.DeletedAt.Equal(tt.deleted) {
+				t.Errorf("DeletedAt = %v, want %v", item.DeletedAt, tt.deleted)
+			}
+		})
+	}
+}
+
+func TestTrashRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	if _, err := deviceOf(root); err != nil {
+		t.Skipf("no trash on this platform: %v", err)
+	}
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	venv := filepath.Join(root, "my app", ".venv")
+	const cfg = "home = /usr/bin\n"
+	makeVenv := func() {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(venv, "bin"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(venv, "pyvenv.cfg"), []byte(cfg), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(venv, "bin", "python"), make([]byte, 100), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Trash the same venv twice; the second gets another name in the trash
+	var trashed []string
+	for i := 0; i < 2; i++ {
+		makeVenv()
+		path, err := MoveToTrash(venv)
+		if err != nil {
+			t.Fatalf("MoveToTrash() error = %v", err)
+		}
+		if _, err := os.Lstat(venv); !os.IsNotExist(err) {
+			t.Fatalf("%s still exists after trashing: %v", venv, err)
+		}
+		trashed = append(trashed, path)
+	}
+	if trashed[0] == trashed[1] {
+		t.Fatalf("both venvs trashed to %s", trashed[0])
+	}
+
+	listed := func() []TrashedItem {
+		t.Helper()
+		items, err := ListTrashedVenvs()
+		if err != nil {
+			t.Fatalf("ListTrashedVenvs() error = %v", err)
+		}
+		var ours []TrashedItem
+		for _, item := range items {
+			if item.OriginalPath == venv {
+				ours = append(ours, item)
+			}
+		}
+		return ours
+	}
+
+	items := listed()
+	if len(items) != 2 {
+		t.Fatalf("listed %d trashed venvs, want 2", len(items))
+	}
+	for _, item := range items {
+		if want := int64(100 + len(cfg)); item.Kind != model.KindVenv || item.Size != want {
+			t.Errorf("listed {%s %d}, want {%s %d}", item.Kind, item.Size, model.KindVenv, want)
+		}
+	}
+
+	if err := RestoreTrashed(items[0]); err != nil {
+		t.Fatalf("RestoreTrashed() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(venv, "bin", "python")); err != nil {
+		t.Errorf("restored venv incomplete: %v", err)
+	}
+
+	// The other one would overwrite the restored venv
+	if err := RestoreTrashed(items[1]); err == nil {
+		t.Error("RestoreTrashed() over an existing venv succeeded")
+	}
+	if left := listed(); len(left) != 1 || left[0].Name != items[1].Name {
+		t.Errorf("after restoring, listed %v, want only %s", left, items[1].Name)
+	}
+}
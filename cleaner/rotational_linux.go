@@ -0,0 +1,33 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isRotational reports whether the block device dev is a spinning disk, using
+// /sys/dev/block/MAJOR:MINOR/queue/rotational (of the parent disk for partitions)
+func isRotational(dev uint64) bool {
+	major := ((dev >> 8) & 0xfff) | ((dev >> 32) &^ 0xfff)
+	minor := (dev & 0xff) | ((dev >> 12) &^ 0xff)
+	base := fmt.Sprintf("/sys/dev/block/%d:%d", major, minor)
+
+	// The entry is a symlink into /sys/devices; a partition's directory sits
+	// inside its disk's, so the disk's queue is one level up from the target
+	resolved, err := filepath.EvalSymlinks(base)
+	if err != nil {
+		return false
+	}
+
+	for _, path := range []string{
+		filepath.Join(resolved, "queue", "rotational"),
+		filepath.Join(filepath.Dir(resolved), "queue", "rotational"),
+	} {
+		if data, err := os.ReadFile(path); err == nil {
+			return strings.TrimSpace(string(data)) == "1"
+		}
+	}
+	return false
+}
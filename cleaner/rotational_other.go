@@ -0,0 +1,8 @@
+//go:build !linux
+
+package cleaner
+
+// isRotational cannot tell disk types apart outside Linux, so no device is treated as rotational
+func isRotational(dev uint64) bool {
+	return false
+}
@@ -0,0 +1,189 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Implementation of the freedesktop.org Trash specification:
+// https://specifications.freedesktop.org/trash-spec/trashspec-latest.html
+
+// trashInfoTimeFormat is the DeletionDate format required by the spec (local time, no zone)
+const trashInfoTimeFormat = "2006-01-02T15:04:05"
+
+// HomeTrashDir returns the user's home trash, $XDG_DATA_HOME/Trash
+func HomeTrashDir() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" || !filepath.IsAbs(dataHome) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "Trash"), nil
+}
+
+// MoveToTrash moves path into the trash directory for its filesystem and
+// returns the location of the trashed file.
+func MoveToTrash(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	dir, topdir, err := trashDirFor(absPath)
+	if err != nil {
+		return "", err
+	}
+
+	filesDir := filepath.Join(dir, "files")
+	infoDir := filepath.Join(dir, "info")
+	for _, d := range []string{filesDir, infoDir} {
+		if err := os.MkdirAll(d, 0o700); err != nil {
+			return "", fmt.Errorf("cannot create trash directory: %w", err)
+		}
+	}
+
+	// Home trash records absolute paths, per-mount trashes paths relative to the mount
+	originalPath := absPath
+	if topdir != "" {
+		if rel, err := filepath.Rel(topdir, absPath); err == nil {
+			originalPath = rel
+		}
+	}
+
+	name, infoPath, err := reserveTrashName(infoDir, filepath.Base(absPath), originalPath)
+	if err != nil {
+		return "", err
+	}
+
+	trashedPath := filepath.Join(filesDir, name)
+	if err := os.Rename(absPath, trashedPath); err != nil {
+		os.Remove(infoPath)
+		return "", fmt.Errorf("cannot move to trash: %w", err)
+	}
+
+	return trashedPath, nil
+}
+
+// trashDirFor picks the trash directory for absPath: the home trash when the
+// file lives on the same filesystem, otherwise a trash at the top of its mount.
+// topdir is empty for the home trash.
+func trashDirFor(absPath string) (dir, topdir string, err error) {
+	homeTrash, err := HomeTrashDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	fileDev, err := deviceOf(absPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	// The home trash may not exist yet, so compare against its nearest existing ancestor
+	if homeDev, err := deviceOf(existingAncestor(homeTrash)); err == nil && homeDev == fileDev {
+		return homeTrash, "", nil
+	}
+
+	topdir, err = mountPoint(absPath, fileDev)
+	if err != nil {
+		return "", "", err
+	}
+
+	uid := strconv.Itoa(os.Getuid())
+
+	// $topdir/.Trash/$uid, only if .Trash is a real directory with the sticky bit set
+	adminTrash := filepath.Join(topdir, ".Trash")
+	if info, err := os.Lstat(adminTrash); err == nil && info.IsDir() && info.Mode()&os.ModeSticky != 0 {
+		userTrash := filepath.Join(adminTrash, uid)
+		if err := os.MkdirAll(userTrash, 0o700); err == nil {
+			return userTrash, topdir, nil
+		}
+	}
+
+	// Otherwise $topdir/.Trash-$uid
+	return filepath.Join(topdir, ".Trash-"+uid), topdir, nil
+}
+
+// reserveTrashName atomically creates the .trashinfo file for a unique name in infoDir
+func reserveTrashName(infoDir, base, originalPath string) (name, infoPath string, err error) {
+	content := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		escapeTrashPath(originalPath), time.Now().Format(trashInfoTimeFormat))
+
+	for i := 1; i < 10000; i++ {
+		name = base
+		if i > 1 {
+			name = fmt.Sprintf("%s.%d", base, i)
+		}
+		infoPath = filepath.Join(infoDir, name+".trashinfo")
+
+		f, err := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("cannot write trash info: %w", err)
+		}
+
+		_, err = f.WriteString(content)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(infoPath)
+			return "", "", fmt.Errorf("cannot write trash info: %w", err)
+		}
+		return name, infoPath, nil
+	}
+
+	return "", "", fmt.Errorf("cannot find a free name for %s in trash", base)
+}
+
+// escapeTrashPath URL-encodes every path segment, keeping the separators
+func escapeTrashPath(path string) string {
+	segments := strings.Split(filepath.ToSlash(path), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// existingAncestor returns path or its nearest parent that exists
+func existingAncestor(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// mountPoint walks up from absPath to the topmost directory still on device dev
+func mountPoint(absPath string, dev uint64) (string, error) {
+	current := absPath
+	for {
+		parent := filepath.Dir(current)
+		if parent == current {
+			return current, nil
+		}
+		parentDev, err := deviceOf(parent)
+		if err != nil {
+			return "", err
+		}
+		if parentDev != dev {
+			return current, nil
+		}
+		current = parent
+	}
+}
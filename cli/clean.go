@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/raoulg/venvcleaner/cleaner"
+	"github.com/raoulg/venvcleaner/model"
+	"github.com/raoulg/venvcleaner/scanner"
+)
+
+// Filter selects venvs for headless cleaning. Zero values match everything.
+type Filter struct {
+	OlderThan time.Duration // Only venvs not modified for at least this long
+	MinSize   int64         // Only venvs of at least this many bytes, counting what is selected of them
+	Python    string        // Only venvs of this Python version or its releases, e.g. 3.8
+	Broken    bool          // Only venvs that can no longer run
+}
+
+// Match reports whether a venv passes the filter. Set the selection first:
+// MinSize is compared with the size of what would be deleted.
+func (f Filter) Match(venv model.VenvInfo, now time.Time) bool {
+	if f.OlderThan > 0 && now.Sub(venv.LastModified) < f.OlderThan {
+		return false
+	}
+	if f.MinSize > 0 && venv.SelectedSize() < f.MinSize {
+		return false
+	}
+	if f.Python != "" && !scanner.MatchPythonVersion(venv.PythonVersion, f.Python) {
+		return false
+	}
+	if f.Broken && !venv.Broken {
+		return false
+	}
+	return true
+}
+
+// Clean implements `venvcleaner clean`: scan, filter and delete without the TUI
+func Clean(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("clean", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	olderThan := fs.String("older-than", "", "only venvs not modified for this long (e.g. 90d, 2w, 6m, 1y)")
+	minSize := fs.String("min-size", "", "only venvs of at least this size (e.g. 200MB, 1.5GB)")
+	python := fs.String("python", "", "only venvs of this Python version (e.g. 3.8 or 3.8.10)")
+	broken := fs.Bool("broken", false, "only venvs whose base interpreter no longer exists")
+	yes := fs.Bool("yes", false, "delete without asking for confirmation")
+	dryRun := fs.Bool("dry-run", false, "show what would be removed without deleting anything")
+	tool := fs.String("tool", "", "removal tool: native, rip, trash or rm (default: rm, native on Windows)")
+	jobs := fs.Int("jobs", 1, "number of folders to delete concurrently")
+	artifactsOnly := fs.Bool("artifacts-only", false, "only delete Python caches and build artifacts, keep the venvs")
+	scanFlags := scanner.RegisterFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: venvcleaner clean [flags] [path]")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	filter := Filter{Python: *python, Broken: *broken}
+	var err error
+	if *olderThan != "" {
+		if filter.OlderThan, err = parseAge(*olderThan); err != nil {
+			fmt.Fprintf(stderr, "--older-than: %v\n", err)
+			return ExitUsage
+		}
+	}
+	if *minSize != "" {
+		if filter.MinSize, err = parseSize(*minSize); err != nil {
+			fmt.Fprintf(stderr, "--min-size: %v\n", err)
+			return ExitUsage
+		}
+	}
+
+	if err := CheckTool(*tool); err != nil {
+		fmt.Fprintf(stderr, "--tool: %v\n", err)
+		return ExitUsage
+	}
+	scanOpts, err := scanFlags.Options()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitUsage
+	}
+	scanOpts.Artifacts = scanOpts.Artifacts || *artifactsOnly
+
+	startPath, err := PathArg(fs.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		fs.Usage()
+		return ExitUsage
+	}
+	rootPath, err := ResolvePath(startPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitFailure
+	}
+
+	// Ctrl+C during the scan stops it; nothing is deleted after an incomplete scan
+	scanCtx, stopScan := signal.NotifyContext(context.Background(), os.Interrupt)
+	venvs, err := scanAll(scanCtx, rootPath, scanOpts)
+	stopScan()
+	if err != nil {
+		fmt.Fprintf(stderr, "Scan failed: %v\n", err)
+		return ExitFailure
+	}
+
+	// Keep only the venvs matching the filters
+	now := time.Now()
+	var candidates []model.VenvInfo
+	var totalSize int64
+	for _, venv := range venvs {
+		venv.Selected = !*artifactsOnly && venv.VenvPath != ""
+		venv.ArtifactsSelected = len(venv.Artifacts) > 0
+		if venv.HasSelection() && filter.Match(venv, now) {
+			candidates = append(candidates, venv)
+			totalSize += venv.SelectedSize()
+		}
+	}
+
+	if len(candidates) == 0 {
+		fmt.Fprintln(stdout, "No matching virtual environments found.")
+		return ExitOK
+	}
+
+	for _, venv := range candidates {
+		fmt.Fprintf(stdout, "%s\t%s\t%s\n", cleanPath(venv), model.FormatSize(venv.SelectedSize()), venv.LastModified.Format("2006-01-02"))
+	}
+
+	// A dry run deletes nothing, so there is nothing to confirm
+	if !*yes && !*dryRun && !confirm(stdin, stdout, fmt.Sprintf("Delete %d virtual environments (%s)?", len(candidates), model.FormatSize(totalSize))) {
+		fmt.Fprintln(stdout, "Aborted.")
+		return ExitOK
+	}
+
+	// Unattended runs are there to free space, so they delete outright unless told otherwise
+	removalTool := *tool
+	if removalTool == "" {
+		removalTool = cleaner.PermanentRemovalTool()
+	}
+
+	removedLabel, freedLabel := "removed", "Freed %s"
+	if place := cleaner.RecoveryPlace(removalTool); place != "" {
+		freedLabel = "Moved %s to " + place + ", freed once it is emptied"
+	}
+	if *dryRun {
+		removedLabel, freedLabel = "would remove", "Dry run: would free %s"
+	}
+
+	// Ctrl+C stops starting new deletions; the ones in progress finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Delete in the background and print a line for every processed folder
+	progressChan := make(chan model.Progress)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- cleaner.DeleteSelected(ctx, candidates, progressChan, cleaner.Options{Tool: removalTool, DryRun: *dryRun, Jobs: *jobs})
+	}()
+
+	var last model.Progress
+	for p := range progressChan {
+		if errors.Is(p.Err, context.Canceled) {
+			fmt.Fprintf(stderr, "skipped\t%s\n", cleanPath(p.Item))
+		} else if p.Err != nil {
+			fmt.Fprintf(stderr, "failed\t%s\t%v\n", cleanPath(p.Item), p.Err)
+		} else {
+			fmt.Fprintf(stdout, "%s\t%s\t%s\n", removedLabel, cleanPath(p.Item), model.FormatSize(p.Item.SelectedSize()))
+		}
+		last = p
+	}
+
+	err = <-errChan
+	fmt.Fprintf(stdout, freedLabel+"\n", model.FormatSize(last.Size))
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(stderr, "Cancelled, the remaining folders were left untouched.")
+	}
+	if errors.Is(err, cleaner.ErrJournal) {
+		fmt.Fprintf(stderr, "Warning: %v\n", err)
+	}
+	if err != nil {
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+// confirm asks a yes/no question on stdout and reads the answer from stdin
+func confirm(stdin io.Reader, stdout io.Writer, question string) bool {
+	fmt.Fprintf(stdout, "%s (y/N): ", question)
+	answer, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(stdout)
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// cleanPath returns what is shown for a cleaning candidate: the venv, or
+// "artifacts of" its repository when only the repository's artifacts are deleted
+func cleanPath(venv model.VenvInfo) string {
+	if !venv.Selected {
+		return "artifacts of " + venv.RepoPath
+	}
+	return venv.VenvPath
+}
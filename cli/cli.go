@@ -0,0 +1,90 @@
+// Package cli implements the non-interactive venvcleaner subcommands.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/raoulg/venvcleaner/cleaner"
+	"github.com/raoulg/venvcleaner/model"
+	"github.com/raoulg/venvcleaner/scanner"
+)
+
+// Exit codes used by the subcommands
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitUsage   = 2
+)
+
+// ResolvePath converts a user supplied path to an absolute path and checks that it exists
+func ResolvePath(startPath string) (string, error) {
+	absPath, err := filepath.Abs(startPath)
+	if err != nil {
+		return "", fmt.Errorf("error resolving path: %w", err)
+	}
+
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("path does not exist: %s", absPath)
+	}
+
+	return absPath, nil
+}
+
+// CheckTool validates a --tool value; empty means auto-detect
+func CheckTool(tool string) error {
+	if tool == "" || slices.Contains(cleaner.RemovalTools, tool) {
+		return nil
+	}
+	return fmt.Errorf("unknown removal tool %q (use %s)", tool, strings.Join(cleaner.RemovalTools, ", "))
+}
+
+// PathArg returns the single optional path argument, defaulting to ".".
+// Flag parsing stops at the path, so a flag after it is an error rather than
+// something to ignore.
+func PathArg(args []string) (string, error) {
+	switch {
+	case len(args) == 0:
+		return ".", nil
+	case len(args) == 1:
+		return args[0], nil
+	case strings.HasPrefix(args[1], "-"):
+		return "", fmt.Errorf("flag %s given after the path; flags go before it", args[1])
+	default:
+		return "", fmt.Errorf("expected at most one path, got %d", len(args))
+	}
+}
+
+// scan starts a scan of rootPath and returns its results and final error channels
+func scan(ctx context.Context, rootPath string, opts scanner.Options) (<-chan *model.VenvInfo, <-chan error) {
+	results, progress, errc := scanner.ScanForVenvs(ctx, rootPath, opts)
+
+	// Nobody is watching progress without a TUI, but the scanner blocks until it is read
+	go func() {
+		for range progress {
+		}
+	}()
+
+	return results, errc
+}
+
+// scanAll runs a full scan of rootPath and returns every venv found, sorted by venv path
+func scanAll(ctx context.Context, rootPath string, opts scanner.Options) ([]model.VenvInfo, error) {
+	results, errc := scan(ctx, rootPath, opts)
+
+	var venvs []model.VenvInfo
+	for info := range results {
+		venvs = append(venvs, *info)
+	}
+
+	sort.Slice(venvs, func(i, j int) bool {
+		return venvs[i].VenvPath < venvs[j].VenvPath
+	})
+
+	return venvs, <-errc
+}
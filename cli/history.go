@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/raoulg/venvcleaner/cleaner"
+	"github.com/raoulg/venvcleaner/journal"
+	"github.com/raoulg/venvcleaner/model"
+)
+
+// History implements `venvcleaner history`: query the deletion journal
+func History(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	since := fs.String("since", "", "only attempts on or after this date (YYYY-MM-DD or an age like 30d)")
+	until := fs.String("until", "", "only attempts before this date (YYYY-MM-DD or an age like 30d)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: venvcleaner history [--since DATE] [--until DATE]")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return ExitUsage
+	}
+
+	now := time.Now()
+	var from, to time.Time
+	var err error
+	if *since != "" {
+		if from, err = parseDate(*since, now); err != nil {
+			fmt.Fprintf(stderr, "--since: %v\n", err)
+			return ExitUsage
+		}
+	}
+	if *until != "" {
+		if to, err = parseDate(*until, now); err != nil {
+			fmt.Fprintf(stderr, "--until: %v\n", err)
+			return ExitUsage
+		}
+	}
+
+	entries, err := journal.Read(from, to)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error reading deletion journal: %v\n", err)
+		return ExitFailure
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(stdout, "No deletions recorded.")
+		return ExitOK
+	}
+
+	// Entries are oldest first, so months come out in order. Folders moved
+	// to the trash or rip's graveyard still take up their space, so they are
+	// totalled apart.
+	type monthTotal struct {
+		month   string
+		count   int
+		size    int64
+		trashed int64
+	}
+	var months []monthTotal
+	var removed, failed int
+	var reclaimed, trashed int64
+
+	for _, entry := range entries {
+		version := entry.PythonVersion
+		if version == "" {
+			version = "-"
+		}
+		// Entries written before kinds were recorded are all venvs
+		kind := entry.Kind
+		if kind == "" {
+			kind = string(model.KindVenv)
+		}
+		fmt.Fprintf(stdout, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Time.Local().Format("2006-01-02 15:04"), entry.Outcome, model.FormatSize(entry.SizeBytes),
+			kind, version, entry.Tool, entry.VenvPath)
+
+		if entry.Outcome != journal.OutcomeRemoved {
+			failed++
+			continue
+		}
+		removed++
+
+		month := entry.Time.Local().Format("2006-01")
+		if len(months) == 0 || months[len(months)-1].month != month {
+			months = append(months, monthTotal{month: month})
+		}
+		months[len(months)-1].count++
+		if cleaner.RecoveryPlace(entry.Tool) != "" {
+			trashed += entry.SizeBytes
+			months[len(months)-1].trashed += entry.SizeBytes
+		} else {
+			reclaimed += entry.SizeBytes
+			months[len(months)-1].size += entry.SizeBytes
+		}
+	}
+
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "Reclaimed per month:")
+	for _, m := range months {
+		fmt.Fprintf(stdout, "%s\t%d folders\t%s\t%s kept recoverable\n", m.month, m.count, model.FormatSize(m.size), model.FormatSize(m.trashed))
+	}
+	fmt.Fprintf(stdout, "Total: %d folders removed, %s reclaimed, %s moved to the trash or rip's graveyard, %d failed\n",
+		removed, model.FormatSize(reclaimed), model.FormatSize(trashed), failed)
+
+	return ExitOK
+}
@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/raoulg/venvcleaner/model"
+	"github.com/raoulg/venvcleaner/scanner"
+)
+
+// venvRecord is the exported schema of a venv. Field names are part of the
+// public interface of `venvcleaner list`; only ever add new fields at the end.
+type venvRecord struct {
+	RepoPath           string          `json:"repo_path"`
+	VenvPath           string          `json:"venv_path"`
+	SizeBytes          int64           `json:"size_bytes"`
+	LastModified       string          `json:"last_modified"` // RFC 3339
+	HasPyproject       bool            `json:"has_pyproject"`
+	DiskUsage          int64           `json:"disk_usage_bytes"`
+	Reclaimable        int64           `json:"reclaimable_bytes"`
+	Branch             string          `json:"branch"`
+	Worktree           bool            `json:"worktree"`
+	MainRepoPath       string          `json:"main_repo_path"` // Empty unless a worktree
+	Kind               string          `json:"kind"`
+	ProjectMissing     bool            `json:"project_missing"`
+	Group              string          `json:"group"` // The .tox or .nox directory of a test environment
+	ArtifactsBytes     int64           `json:"artifacts_bytes"`
+	Recreatable        bool            `json:"recreatable"`
+	PythonVersion      string          `json:"python_version"`
+	Creator            string          `json:"creator"`
+	BaseInterpreter    string          `json:"base_interpreter"`
+	SystemSitePackages bool            `json:"include_system_site_packages"`
+	Broken             bool            `json:"broken"`
+	BrokenReason       string          `json:"broken_reason"`
+	Packages           []packageRecord `json:"packages,omitempty"` // Only with --packages, not in CSV
+}
+
+// packageRecord is the exported schema of a package installed in a venv
+type packageRecord struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// csvHeader lists the CSV columns, in the same order as venvRecord; nested
+// fields such as the packages are left out
+var csvHeader = []string{"repo_path", "venv_path", "size_bytes", "last_modified", "has_pyproject", "disk_usage_bytes", "reclaimable_bytes", "branch", "worktree", "main_repo_path", "kind", "project_missing", "group", "artifacts_bytes", "recreatable", "python_version", "creator", "base_interpreter", "include_system_site_packages", "broken", "broken_reason"}
+
+func newVenvRecord(venv *model.VenvInfo) venvRecord {
+	return venvRecord{
+		RepoPath:           venv.RepoPath,
+		VenvPath:           venv.VenvPath,
+		SizeBytes:          venv.Size,
+		LastModified:       venv.LastModified.Format(time.RFC3339),
+		HasPyproject:       venv.HasPyproject,
+		DiskUsage:          venv.DiskUsage,
+		Reclaimable:        venv.Reclaimable,
+		Branch:             venv.Branch,
+		Worktree:           venv.Worktree,
+		MainRepoPath:       venv.MainRepoPath,
+		Kind:               string(venv.Kind),
+		ProjectMissing:     venv.ProjectMissing,
+		Group:              venv.Group,
+		ArtifactsBytes:     venv.ArtifactsSize,
+		Recreatable:        venv.Recreatable,
+		PythonVersion:      venv.PythonVersion,
+		Creator:            venv.Creator,
+		BaseInterpreter:    venv.BaseInterpreter,
+		SystemSitePackages: venv.SystemSitePackages,
+		Broken:             venv.Broken,
+		BrokenReason:       venv.BrokenReason,
+	}
+}
+
+// withPackages adds the package inventory of the venv to a record
+func (rec venvRecord) withPackages() (venvRecord, error) {
+	if rec.VenvPath == "" {
+		return rec, nil
+	}
+	packages, err := scanner.Packages(rec.VenvPath)
+	if err != nil {
+		return rec, err
+	}
+	for _, pkg := range packages {
+		rec.Packages = append(rec.Packages, packageRecord{Name: pkg.Name, Version: pkg.Version, SizeBytes: pkg.Size})
+	}
+	return rec, nil
+}
+
+// recordWriter streams records in one output format
+type recordWriter interface {
+	Write(rec venvRecord) error
+	Close() error
+}
+
+// ListUsage is the synopsis of `venvcleaner list`
+const ListUsage = "venvcleaner list [--format json|csv|ndjson] [--packages] " + scanner.FlagSynopsis + " [path]"
+
+// List implements `venvcleaner list`: stream scan results to stdout in a machine-readable format
+func List(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "json", "output format: json, csv or ndjson")
+	scanFlags := scanner.RegisterFlags(fs)
+	withPackages := fs.Bool("packages", false, "also list the packages installed in each venv (json and ndjson only)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: "+ListUsage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	var w recordWriter
+	switch *format {
+	case "json":
+		w = &jsonArrayWriter{out: stdout}
+	case "ndjson":
+		w = &ndjsonWriter{enc: json.NewEncoder(stdout)}
+	case "csv":
+		w = &csvWriter{out: csv.NewWriter(stdout)}
+	default:
+		fmt.Fprintf(stderr, "--format: unknown format %q (use json, csv or ndjson)\n", *format)
+		return ExitUsage
+	}
+	if *withPackages && *format == "csv" {
+		fmt.Fprintln(stderr, "--packages: not available with --format csv")
+		return ExitUsage
+	}
+	scanOpts, err := scanFlags.Options()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitUsage
+	}
+
+	startPath, err := PathArg(fs.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		fs.Usage()
+		return ExitUsage
+	}
+	rootPath, err := ResolvePath(startPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitFailure
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	results, errc := scan(ctx, rootPath, scanOpts)
+	for venv := range results {
+		rec := newVenvRecord(venv)
+		if *withPackages {
+			// The record is still written, without its packages
+			if rec, err = rec.withPackages(); err != nil {
+				fmt.Fprintf(stderr, "Error reading packages of %s: %v\n", rec.VenvPath, err)
+			}
+		}
+		if err := w.Write(rec); err != nil {
+			fmt.Fprintf(stderr, "Error writing output: %v\n", err)
+			// Stop the scan and let it wind down
+			cancel()
+			for range results {
+			}
+			return ExitFailure
+		}
+	}
+
+	// Close the output even after a failed scan so it stays well-formed
+	closeErr := w.Close()
+	if err := <-errc; err != nil {
+		fmt.Fprintf(stderr, "Scan failed: %v\n", err)
+		return ExitFailure
+	}
+	if closeErr != nil {
+		fmt.Fprintf(stderr, "Error writing output: %v\n", closeErr)
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+// jsonArrayWriter writes a single JSON array, one element per line
+type jsonArrayWriter struct {
+	out   io.Writer
+	count int
+}
+
+func (w *jsonArrayWriter) Write(rec venvRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if w.count == 0 {
+		sep = "[\n  "
+	}
+	w.count++
+	_, err = fmt.Fprintf(w.out, "%s%s", sep, data)
+	return err
+}
+
+func (w *jsonArrayWriter) Close() error {
+	if w.count == 0 {
+		_, err := fmt.Fprintln(w.out, "[]")
+		return err
+	}
+	_, err := fmt.Fprintln(w.out, "\n]")
+	return err
+}
+
+// ndjsonWriter writes one JSON object per line
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(rec venvRecord) error {
+	return w.enc.Encode(rec)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+// csvWriter writes a header row followed by one row per record
+type csvWriter struct {
+	out         *csv.Writer
+	wroteHeader bool
+}
+
+func (w *csvWriter) Write(rec venvRecord) error {
+	if !w.wroteHeader {
+		if err := w.out.Write(csvHeader); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+	err := w.out.Write([]string{
+		rec.RepoPath,
+		rec.VenvPath,
+		strconv.FormatInt(rec.SizeBytes, 10),
+		rec.LastModified,
+		strconv.FormatBool(rec.HasPyproject),
+		strconv.FormatInt(rec.DiskUsage, 10),
+		strconv.FormatInt(rec.Reclaimable, 10),
+		rec.Branch,
+		strconv.FormatBool(rec.Worktree),
+		rec.MainRepoPath,
+		rec.Kind,
+		strconv.FormatBool(rec.ProjectMissing),
+		rec.Group,
+		strconv.FormatInt(rec.ArtifactsBytes, 10),
+		strconv.FormatBool(rec.Recreatable),
+		rec.PythonVersion,
+		rec.Creator,
+		rec.BaseInterpreter,
+		strconv.FormatBool(rec.SystemSitePackages),
+		strconv.FormatBool(rec.Broken),
+		rec.BrokenReason,
+	})
+	if err != nil {
+		return err
+	}
+	// Flush per row so consumers see results as they are found
+	w.out.Flush()
+	return w.out.Error()
+}
+
+func (w *csvWriter) Close() error {
+	if !w.wroteHeader {
+		if err := w.out.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	w.out.Flush()
+	return w.out.Error()
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/raoulg/venvcleaner/cleaner"
+	"github.com/raoulg/venvcleaner/model"
+)
+
+// Restore implements `venvcleaner restore --list` and `venvcleaner restore PATH...`.
+// Without arguments the interactive restore screen is shown instead.
+func Restore(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	list := fs.Bool("list", false, "list trashed virtual environments and other folders")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: venvcleaner restore                  (interactive)")
+		fmt.Fprintln(stderr, "       venvcleaner restore --list")
+		fmt.Fprintln(stderr, "       venvcleaner restore PATH...           (venv or repository path)")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if !*list && fs.NArg() == 0 {
+		fs.Usage()
+		return ExitUsage
+	}
+
+	items, err := cleaner.ListTrashedVenvs()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error reading trash: %v\n", err)
+		return ExitFailure
+	}
+
+	if *list {
+		for _, item := range items {
+			fmt.Fprintf(stdout, "%s\t%s\t%s\t%s\n",
+				item.DeletedAt.Format("2006-01-02 15:04"), model.FormatSize(item.Size), item.Kind, item.OriginalPath)
+		}
+		return ExitOK
+	}
+
+	exitCode := ExitOK
+	for _, arg := range fs.Args() {
+		path, err := filepath.Abs(arg)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error resolving path: %v\n", err)
+			exitCode = ExitFailure
+			continue
+		}
+
+		item, found := trashedFor(items, path)
+		if !found {
+			fmt.Fprintf(stderr, "Nothing trashed found for %s\n", path)
+			exitCode = ExitFailure
+			continue
+		}
+		if err := cleaner.RestoreTrashed(item); err != nil {
+			fmt.Fprintln(stderr, err)
+			exitCode = ExitFailure
+		} else {
+			fmt.Fprintf(stdout, "restored\t%s\t%s\n", item.OriginalPath, model.FormatSize(item.Size))
+		}
+	}
+
+	return exitCode
+}
+
+// trashedFor returns the item to restore for path: the latest deletion of that
+// exact path, or else of a folder directly in it, preferring venvs over the
+// repository's other folders such as dist or build. Items are sorted newest first.
+func trashedFor(items []cleaner.TrashedItem, path string) (cleaner.TrashedItem, bool) {
+	var inside []cleaner.TrashedItem
+	for _, item := range items {
+		if item.OriginalPath == path {
+			return item, true
+		}
+		if filepath.Dir(item.OriginalPath) == path {
+			inside = append(inside, item)
+		}
+	}
+	for _, item := range inside {
+		if item.Kind == model.KindVenv {
+			return item, true
+		}
+	}
+	if len(inside) == 0 {
+		return cleaner.TrashedItem{}, false
+	}
+	return inside[0], true
+}
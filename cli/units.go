@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseAge parses an age such as "90d", "2w", "6m", "1y", or a value accepted
+// by time.ParseDuration such as "36h". As "m" means months, so "90m" is 90
+// months, durations with minutes such as "1h30m" are rejected as ambiguous.
+func parseAge(orig string) (time.Duration, error) {
+	s := strings.TrimSpace(strings.ToLower(orig))
+	if s == "" {
+		return 0, fmt.Errorf("empty age")
+	}
+
+	units := map[byte]time.Duration{
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+		'm': 30 * 24 * time.Hour,
+		'y': 365 * 24 * time.Hour,
+	}
+
+	if unit, ok := units[s[len(s)-1]]; ok {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err == nil && n >= 0 {
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	if strings.ContainsRune(s, 'm') {
+		return 0, fmt.Errorf("invalid age %q (m means months; give shorter ages in hours, e.g. 36h)", orig)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 90d, 2w, 6m, 1y)", orig)
+	}
+	return d, nil
+}
+
+// parseDate parses a date such as "2026-01-31" (local midnight) or an age
+// relative to now such as "30d", meaning 30 days ago
+func parseDate(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(s), time.Local); err == nil {
+		return t, nil
+	}
+	age, err := parseAge(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD or an age like 30d)", s)
+	}
+	return now.Add(-age), nil
+}
+
+// parseSize parses a size such as "200MB", "1.5G", "512k" or a plain number of bytes.
+// Units are binary (1 KB = 1024 bytes), matching the sizes shown in the TUI.
+func parseSize(orig string) (int64, error) {
+	s := strings.TrimSpace(strings.ToUpper(orig))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "IB"), "B")
+	if s == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+
+	multiplier := int64(1)
+	if i := strings.IndexByte("KMGTP", s[len(s)-1]); i >= 0 {
+		multiplier = int64(1) << (10 * (i + 1))
+		s = s[:len(s)-1]
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 200MB, 1.5GB)", orig)
+	}
+	return int64(n * float64(multiplier)), nil
+}
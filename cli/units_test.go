@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		age     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"90d", 90 * day, false},
+		{"2w", 14 * day, false},
+		{"6m", 180 * day, false},
+		{"90m", 2700 * day, false},
+		{"1y", 365 * day, false},
+		{" 3D ", 3 * day, false},
+		{"0d", 0, false},
+		{"36h", 36 * time.Hour, false},
+		{"1h30m", 0, true},
+		{"500ms", 0, true},
+		{"", 0, true},
+		{"d", 0, true},
+		{"-5d", 0, true},
+		{"-1h", 0, true},
+		{"ninety days", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseAge(tt.age)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseAge(%q) error = %v, want error %v", tt.age, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAge(%q) = %v, want %v", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{"512", 512, false},
+		{"512B", 512, false},
+		{"512k", 512 << 10, false},
+		{"200MB", 200 << 20, false},
+		{"200MiB", 200 << 20, false},
+		{"1.5GB", 3 << 29, false},
+		{"2 T", 2 << 40, false},
+		{" 1g ", 1 << 30, false},
+		{"", 0, true},
+		{"MB", 0, true},
+		{"-1MB", 0, true},
+		{"lots", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSize(tt.size)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSize(%q) error = %v, want error %v", tt.size, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseSize(%q) = %d, want %d", tt.size, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Package journal keeps a persistent, append-only record of deletion attempts.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+)
+
+// Outcomes of a deletion attempt
+const (
+	OutcomeRemoved = "removed"
+	OutcomeFailed  = "failed"
+)
+
+// Entry is one deletion attempt, stored as a single JSON line
+type Entry struct {
+	Time          time.Time `json:"time"`
+	User          string    `json:"user"`
+	Tool          string    `json:"tool"`
+	Kind          string    `json:"kind,omitempty"`
+	VenvPath      string    `json:"venv_path"`
+	RepoPath      string    `json:"repo_path"`
+	SizeBytes     int64     `json:"size_bytes"`
+	PythonVersion string    `json:"python_version,omitempty"`
+	Outcome       string    `json:"outcome"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// Path returns the journal file, $XDG_STATE_HOME/venvcleaner/journal.jsonl
+func Path() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" || !filepath.IsAbs(stateHome) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateHome, "venvcleaner", "journal.jsonl"), nil
+}
+
+// CurrentUser returns the name recorded in new entries
+func CurrentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// Append adds an entry to the end of the journal, creating it if needed
+func Append(entry Entry) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	// A single write of one line keeps concurrent appends from interleaving
+	_, err = f.Write(append(data, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Read returns the entries with since <= Time < until, oldest first.
+// Zero times leave that end of the range open. A missing journal is empty.
+func Read(since, until time.Time) ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	lines := bufio.NewScanner(f)
+	lines.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lines.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(lines.Bytes(), &entry); err != nil {
+			// Skip lines truncated by a crash rather than losing the whole history
+			continue
+		}
+		if !since.IsZero() && entry.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !entry.Time.Before(until) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, lines.Err()
+}
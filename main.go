@@ -1,40 +1,83 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/raoulg/venvcleaner/cleaner"
+	"github.com/raoulg/venvcleaner/cli"
 	"github.com/raoulg/venvcleaner/scanner"
 	"github.com/raoulg/venvcleaner/ui"
 )
 
 func main() {
-	// Parse command line arguments
-	startPath := "."
+	// Dispatch non-interactive subcommands
 	if len(os.Args) > 1 {
-		startPath = os.Args[1]
+		switch os.Args[1] {
+		case "clean":
+			os.Exit(cli.Clean(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+		case "list":
+			os.Exit(cli.List(os.Args[2:], os.Stdout, os.Stderr))
+		case "history":
+			os.Exit(cli.History(os.Args[2:], os.Stdout, os.Stderr))
+		case "restore":
+			if len(os.Args) > 2 {
+				os.Exit(cli.Restore(os.Args[2:], os.Stdout, os.Stderr))
+			}
+			runProgram(ui.NewRestoreModel(Version))
+			return
+		}
+	}
+
+	// Parse command line arguments
+	dryRun := flag.Bool("dry-run", false, "simulate cleaning without deleting anything")
+	tool := flag.String("tool", "", "removal tool: native, rip, trash or rm (default: auto-detect)")
+	jobs := flag.Int("jobs", 1, "number of folders to delete concurrently")
+	scanFlags := scanner.RegisterFlags(flag.CommandLine)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: venvcleaner [flags] [path]\n")
+		fmt.Fprintf(os.Stderr, "       venvcleaner clean [flags] [path]\n")
+		fmt.Fprintf(os.Stderr, "       %s\n", cli.ListUsage)
+		fmt.Fprintf(os.Stderr, "       venvcleaner restore [--list | PATH...]\n")
+		fmt.Fprintf(os.Stderr, "       venvcleaner history [--since DATE] [--until DATE]\n\n")
+		flag.PrintDefaults()
 	}
+	flag.Parse()
 
-	// Convert to absolute path
-	absPath, err := filepath.Abs(startPath)
+	if err := cli.CheckTool(*tool); err != nil {
+		fmt.Fprintf(os.Stderr, "--tool: %v\n", err)
+		os.Exit(2)
+	}
+	scanOpts, err := scanFlags.Options()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error resolving path: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
 	}
 
-	// Check if path exists
-	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Path does not exist: %s\n", absPath)
+	startPath, err := cli.PathArg(flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Convert to absolute path and check that it exists
+	absPath, err := cli.ResolvePath(startPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
 
-	// Start scanning in background
-	scanResults, scanProgress := scanner.ScanForVenvs(absPath)
+	// Initialize Bubbletea program, which starts scanning in the background
+	model := ui.NewModel(context.Background(), absPath, scanOpts, Version, cleaner.Options{Tool: *tool, DryRun: *dryRun, Jobs: *jobs})
+	runProgram(model)
+}
 
-	// Initialize Bubbletea program with full-screen mode
-	model := ui.NewModel(absPath, scanResults, scanProgress, Version)
+// runProgram runs a Bubbletea model in full-screen mode
+func runProgram(model tea.Model) {
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
 	// Run the program
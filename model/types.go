@@ -1,15 +1,160 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
-// VenvInfo represents a Python virtual environment found in a git repository
+// VenvInfo represents a Python virtual environment, usually found in a git repository
 type VenvInfo struct {
-	RepoPath     string    // Path to the git repository
-	VenvPath     string    // Path to the .venv folder
-	HasPyproject bool      // Whether pyproject.toml exists in the repo
-	LastModified time.Time // Most recent modification time in .venv
-	Size         int64     // Total size of .venv in bytes
-	Selected     bool      // Whether this venv is selected for deletion
+	Kind               EnvKind               // Tool that created the environment and where it lives
+	RepoPath           string                // Path to the git repository, or the project of a centrally stored venv; "" if none
+	MainRepoPath       string                // For a linked worktree, path of the main working tree
+	Branch             string                // Branch checked out in the repository, or a short commit hash
+	Worktree           bool                  // Whether the repository is a linked git worktree
+	ProjectMissing     bool                  // For a centrally stored venv, whether its project directory is gone
+	VenvPath           string                // Path to the .venv folder, or the dependency folder of another kind
+	Group              string                // Directory holding related environments, such as .tox; "" if none
+	HasPyproject       bool                  // Whether pyproject.toml exists in the repo
+	Recreatable        bool                  // Whether the project has the manifest, and lockfile if needed, to recreate the folder
+	PythonVersion      string                // Python version from pyvenv.cfg, e.g. 3.12.4; "" if unknown
+	Creator            string                // Tool that created the venv: venv, uv or virtualenv; "" if unknown
+	BaseInterpreter    string                // Interpreter the venv was created from, or the directory holding it
+	SystemSitePackages bool                  // Whether the venv also sees the base interpreter's site-packages
+	Broken             bool                  // Whether the venv can no longer run, e.g. because its base interpreter is gone
+	BrokenReason       string                // Why the venv is broken; "" if it is not
+	LastModified       time.Time             // Most recent modification time in .venv
+	Size               int64                 // Total size of .venv in bytes (apparent size)
+	DiskUsage          int64                 // Bytes allocated on disk, counting hard-linked files once
+	Reclaimable        int64                 // Bytes freed by deleting only this venv
+	SharedFiles        map[FileID]SharedFile // Files in .venv with hard links, for totals across venvs
+	FileCount          int                   // Number of files in .venv
+	DirCount           int                   // Number of directories in .venv, including itself
+	LargestDirs        []DirSize             // Biggest packages or top-level folders in .venv, largest first
+	Selected           bool                  // Whether this venv is selected for deletion
+	Artifacts          []Artifact            // Caches and build outputs of the repository, on the entry carrying them
+	ArtifactsSize      int64                 // Total size of Artifacts in bytes
+	ArtifactsSelected  bool                  // Whether the artifacts are selected for deletion
+}
+
+// HasSelection reports whether the venv, its artifacts or both are selected for deletion
+func (v VenvInfo) HasSelection() bool {
+	return v.Selected || v.ArtifactsSelected
+}
+
+// Path returns the venv path, or the repository path for an entry that only
+// holds artifacts
+func (v VenvInfo) Path() string {
+	if v.VenvPath == "" {
+		return v.RepoPath
+	}
+	return v.VenvPath
+}
+
+// SelectedSize returns the size of what is selected for deletion
+func (v VenvInfo) SelectedSize() int64 {
+	var size int64
+	if v.Selected {
+		size += v.Size
+	}
+	if v.ArtifactsSelected {
+		size += v.ArtifactsSize
+	}
+	return size
+}
+
+// FormatSize formats a number of bytes in binary units, such as "1.5 GB"
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// Artifact is a cache or build output directory that is regenerated when needed,
+// such as __pycache__, .pytest_cache or dist
+type Artifact struct {
+	Path         string    // Path to the directory
+	Size         int64     // Total size in bytes
+	LastModified time.Time // Most recent modification time in the directory
+}
+
+// Package is a distribution installed in a venv, read from its .dist-info metadata
+type Package struct {
+	Name    string // Distribution name, e.g. numpy
+	Version string // Installed version, e.g. 2.1.0
+	Size    int64  // Total size in bytes of the files it installed
+}
+
+// EnvKind tells which tool created an environment or dependency folder and where it lives
+type EnvKind string
+
+const (
+	KindVenv      EnvKind = "venv"      // A virtualenv inside a project directory, such as .venv
+	KindPoetry    EnvKind = "poetry"    // A Poetry virtualenv in its central cache
+	KindPipenv    EnvKind = "pipenv"    // A Pipenv virtualenv in WORKON_HOME
+	KindConda     EnvKind = "conda"     // A conda or mamba environment
+	KindTox       EnvKind = "tox"       // A tox test environment in .tox
+	KindNox       EnvKind = "nox"       // A nox session environment in .nox
+	KindArtifacts EnvKind = "artifacts" // No environment, only the artifacts of a repository
+	KindNode      EnvKind = "node"      // A node_modules folder next to a package.json
+	KindRust      EnvKind = "rust"      // A Cargo target folder next to a Cargo.toml
+	KindGradle    EnvKind = "gradle"    // A Gradle project cache, .gradle
+	KindVendor    EnvKind = "vendor"    // A Go or Composer vendor folder
+)
+
+// FileID identifies a file on disk independently of its paths
+type FileID struct {
+	Dev uint64 // Device holding the file
+	Ino uint64 // Inode number on that device
+}
+
+// SharedFile is a file with several hard links, of which Seen are inside a venv.
+// Its blocks are only freed once all Links are deleted.
+type SharedFile struct {
+	Usage int64  // Bytes allocated on disk
+	Links uint64 // Total number of hard links
+	Seen  uint64 // Number of links inside the venv
+}
+
+// ReclaimableSize returns how many bytes deleting all the given venvs frees.
+// Files hard-linked between them count once all their links are deleted,
+// files also linked from elsewhere (such as a package cache) not at all.
+func ReclaimableSize(venvs []VenvInfo) int64 {
+	var total int64
+	shared := make(map[FileID]SharedFile)
+
+	for _, venv := range venvs {
+		total += venv.Reclaimable
+		for id, file := range venv.SharedFiles {
+			if file.Seen >= file.Links {
+				total -= file.Usage // Counted in Reclaimable, added back below
+			}
+			sum := shared[id]
+			sum.Usage, sum.Links = file.Usage, file.Links
+			sum.Seen += file.Seen
+			shared[id] = sum
+		}
+	}
+
+	for _, file := range shared {
+		if file.Seen >= file.Links {
+			total += file.Usage
+		}
+	}
+	return total
+}
+
+// DirSize is the total size of the files below one directory
+type DirSize struct {
+	Path string // Path relative to the venv, e.g. lib/python3.12/site-packages/torch
+	Size int64  // Total size in bytes
 }
 
 // UIState represents the current state of the UI
@@ -30,13 +175,16 @@ const (
 	SortByTime SortMode = iota
 	SortBySize
 	SortByName
+	SortByVersion
 )
 
-// Progress represents deletion progress
+// Progress represents deletion progress. One update is sent per processed item.
 type Progress struct {
-	Current int
-	Total   int
-	Size    int64
+	Current int      // Number of items processed so far, including failures
+	Total   int      // Number of items to process
+	Size    int64    // Bytes freed so far
+	Item    VenvInfo // The item that was just processed; on failure, only what is left of its selection
+	Err     error    // Why deleting Item failed, nil on success
 }
 
 // ScanProgress represents scanning progress
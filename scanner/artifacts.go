@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/raoulg/venvcleaner/model"
+)
+
+// artifactDirs are the cache directories that are always safe to delete
+var artifactDirs = []string{"__pycache__", ".pytest_cache", ".mypy_cache", ".ruff_cache"}
+
+// buildDirs are only artifacts next to the packaging files that produce them,
+// and only when git tracks none of their files
+var buildDirs = []string{"build", "dist"}
+
+// isArtifact reports whether the directory at path, in the repository at
+// repoPath and next to the given entries, holds caches or build outputs
+func isArtifact(repoPath, path string, entries []os.DirEntry) bool {
+	name := filepath.Base(path)
+	if slices.Contains(artifactDirs, name) || strings.HasSuffix(name, ".egg-info") {
+		return true
+	}
+	if !slices.Contains(buildDirs, name) {
+		return false
+	}
+	packaged := slices.ContainsFunc(entries, func(entry os.DirEntry) bool {
+		switch entry.Name() {
+		case "pyproject.toml", "setup.py", "setup.cfg":
+			return !entry.IsDir()
+		}
+		return false
+	})
+	return packaged && isUntracked(repoPath, path)
+}
+
+// measureArtifact returns the size and last modification of an artifact directory
+func (w *walker) measureArtifact(path string) model.Artifact {
+	stats, _ := StatVenv(w.ctx, path)
+	return model.Artifact{Path: path, Size: stats.Size, LastModified: stats.LastModified}
+}
+
+// repoArtifacts collects the artifacts of one repository while its subtree
+// is scanned
+type repoArtifacts struct {
+	path      string
+	artifacts []model.Artifact
+}
+
+// entry returns the entry reporting the artifacts of the repository, dated by
+// the newest of them
+func (r *repoArtifacts) entry() *model.VenvInfo {
+	entry := &model.VenvInfo{Kind: model.KindArtifacts, RepoPath: r.path, Artifacts: r.artifacts}
+	entry.Branch, entry.MainRepoPath, entry.Worktree = inspectRepo(r.path)
+	_, err := os.Stat(filepath.Join(r.path, "pyproject.toml"))
+	entry.HasPyproject = err == nil
+	for _, artifact := range r.artifacts {
+		entry.ArtifactsSize += artifact.Size
+		if artifact.LastModified.After(entry.LastModified) {
+			entry.LastModified = artifact.LastModified
+		}
+	}
+	return entry
+}
@@ -0,0 +1,165 @@
+package scanner
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CondaEnvironmentsFile returns the registry conda and mamba keep of every
+// environment they created, ~/.conda/environments.txt
+func CondaEnvironmentsFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".conda", "environments.txt"), nil
+}
+
+// IsCondaEnv reports whether dir is a conda environment, which has a conda-meta directory
+func IsCondaEnv(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "conda-meta"))
+	return err == nil && info.IsDir()
+}
+
+// isCondaBase reports whether a conda environment is the base installation itself
+func isCondaBase(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "condabin"))
+	return err == nil
+}
+
+// CondaEnvs returns the prefixes of all conda environments registered in
+// environments.txt or found in the configured envs_dirs, except base
+// installations, which cannot be removed on their own
+func CondaEnvs() []string {
+	var candidates []string
+
+	if registry, err := CondaEnvironmentsFile(); err == nil {
+		if lines, err := readLines(registry); err == nil {
+			candidates = append(candidates, lines...)
+		}
+	}
+
+	for _, dir := range condaEnvsDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				candidates = append(candidates, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+
+	var envs []string
+	seen := make(map[string]bool)
+	for _, prefix := range candidates {
+		prefix = filepath.Clean(prefix)
+		if seen[prefix] || !IsCondaEnv(prefix) || isCondaBase(prefix) {
+			continue
+		}
+		seen[prefix] = true
+		envs = append(envs, prefix)
+	}
+	return envs
+}
+
+// condaEnvsDirs returns the directories conda creates named environments in:
+// $CONDA_ENVS_PATH, the envs_dirs of the user's .condarc files and ~/.conda/envs
+func condaEnvsDirs() []string {
+	var dirs []string
+	if path := os.Getenv("CONDA_ENVS_PATH"); path != "" {
+		dirs = append(dirs, filepath.SplitList(path)...)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return dirs
+	}
+
+	condarcs := []string{
+		filepath.Join(home, ".condarc"),
+		filepath.Join(home, ".config", "conda", ".condarc"),
+		os.Getenv("CONDARC"),
+	}
+	for _, condarc := range condarcs {
+		if condarc == "" {
+			continue
+		}
+		for _, dir := range readEnvsDirs(condarc) {
+			if rest, ok := strings.CutPrefix(dir, "~"); ok {
+				dir = filepath.Join(home, rest)
+			}
+			dirs = append(dirs, os.ExpandEnv(dir))
+		}
+	}
+
+	return append(dirs, filepath.Join(home, ".conda", "envs"))
+}
+
+// readEnvsDirs reads the envs_dirs list from a .condarc file. Only the two
+// YAML list forms conda itself writes are understood: a block of "- item"
+// lines and an inline [a, b] list.
+func readEnvsDirs(condarc string) []string {
+	lines, err := readLines(condarc)
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	inList := false
+	for _, line := range lines {
+		if value, ok := strings.CutPrefix(line, "envs_dirs:"); ok {
+			value = strings.TrimSpace(value)
+			if inline, ok := strings.CutPrefix(value, "["); ok {
+				for _, item := range strings.Split(strings.TrimSuffix(inline, "]"), ",") {
+					if item = unquote(item); item != "" {
+						dirs = append(dirs, item)
+					}
+				}
+				continue
+			}
+			inList = true
+			continue
+		}
+		if !inList {
+			continue
+		}
+		item, ok := strings.CutPrefix(line, "-")
+		if !ok {
+			// The next key ends the list
+			inList = false
+			continue
+		}
+		if item = unquote(item); item != "" {
+			dirs = append(dirs, item)
+		}
+	}
+	return dirs
+}
+
+// unquote trims whitespace and YAML quotes from a list item
+func unquote(item string) string {
+	return strings.Trim(strings.TrimSpace(item), `"'`)
+}
+
+// readLines returns the non-empty, non-comment lines of a file, trimmed
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
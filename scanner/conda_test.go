@@ -0,0 +1,47 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReadEnvsDirs(t *testing.T) {
+	tests := []struct {
+		name    string
+		condarc string
+		want    []string
+	}{
+		{
+			name:    "block list",
+			condarc: "channels:\n  - conda-forge\nenvs_dirs:\n  - /opt/envs\n  - \"~/envs\"\nauto_activate_base: false\n",
+			want:    []string{"/opt/envs", "~/envs"},
+		},
+		{
+			name:    "inline list",
+			condarc: "envs_dirs: [/opt/envs, '/data/conda envs']\n",
+			want:    []string{"/opt/envs", "/data/conda envs"},
+		},
+		{
+			name:    "comments and blank lines inside the list",
+			condarc: "envs_dirs:\n  # shared first\n  - /shared/envs\n\n  - /home/me/envs\n",
+			want:    []string{"/shared/envs", "/home/me/envs"},
+		},
+		{
+			name:    "no envs_dirs",
+			condarc: "channels:\n  - defaults\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".condarc")
+			if err := os.WriteFile(path, []byte(tt.condarc), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if got := readEnvsDirs(path); !slices.Equal(got, tt.want) {
+				t.Errorf("readEnvsDirs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
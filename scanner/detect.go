@@ -0,0 +1,232 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/raoulg/venvcleaner/model"
+)
+
+// Detector recognises one kind of dependency or build folder that the tools
+// of its ecosystem can recreate, such as a Python venv or node_modules
+type Detector interface {
+	// Kind returns the kind of folder found, as selected with Options.Kinds
+	Kind() model.EnvKind
+	// Match reports whether the directory at path is such a folder
+	Match(path string) bool
+	// Stat measures the folder; repoPath is "" outside any repository
+	Stat(ctx context.Context, repoPath, path string) (*model.VenvInfo, error)
+	// Recreatable reports whether the project the folder belongs to has what
+	// it takes to recreate it, such as a manifest and a lockfile
+	Recreatable(repoPath, path string) bool
+}
+
+// detectors are all known detectors, in the order they are tried
+var detectors = []Detector{
+	venvDetector{},
+	folderDetector{
+		kind:      model.KindNode,
+		name:      "node_modules",
+		manifests: []string{"package.json"},
+		lockfiles: []string{"package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml", "bun.lockb", "bun.lock"},
+	},
+	folderDetector{
+		// Build output only, the dependencies themselves live in ~/.cargo
+		kind:      model.KindRust,
+		name:      "target",
+		manifests: []string{"Cargo.toml"},
+	},
+	folderDetector{
+		// The project cache; the downloaded dependencies live in ~/.gradle
+		kind:      model.KindGradle,
+		name:      ".gradle",
+		manifests: []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"},
+	},
+	folderDetector{
+		// Often committed, and then part of the project's source
+		kind:        model.KindVendor,
+		name:        "vendor",
+		manifests:   []string{"go.mod", "composer.json"},
+		lockfiles:   []string{"go.sum", "composer.lock"},
+		skipTracked: true,
+	},
+}
+
+// Kinds returns the kinds of folder Options.Kinds can select
+func Kinds() []model.EnvKind {
+	var kinds []model.EnvKind
+	for _, d := range detectors {
+		kinds = append(kinds, d.Kind())
+	}
+	return kinds
+}
+
+// ParseKinds parses a comma-separated list of kinds, such as "venv,node,rust"
+func ParseKinds(list string) ([]model.EnvKind, error) {
+	var kinds []model.EnvKind
+	for _, name := range strings.Split(list, ",") {
+		kind := model.EnvKind(strings.TrimSpace(name))
+		if kind == "" {
+			continue
+		}
+		if detectorFor(kind) == nil {
+			return nil, fmt.Errorf("unknown kind %q (use %s)", kind, joinKinds(Kinds()))
+		}
+		kinds = append(kinds, kind)
+	}
+	if len(kinds) == 0 {
+		return nil, fmt.Errorf("no kinds given (use %s)", joinKinds(Kinds()))
+	}
+	return kinds, nil
+}
+
+// joinKinds lists kinds for messages, separated by commas
+func joinKinds(kinds []model.EnvKind) string {
+	names := make([]string, len(kinds))
+	for i, kind := range kinds {
+		names[i] = string(kind)
+	}
+	return strings.Join(names, ", ")
+}
+
+// detectorFor returns the detector of a kind, or nil if there is none
+func detectorFor(kind model.EnvKind) Detector {
+	for _, d := range detectors {
+		if d.Kind() == kind {
+			return d
+		}
+	}
+	return nil
+}
+
+// enabledDetectors returns the detectors for kinds; no kinds means only venvs
+func enabledDetectors(kinds []model.EnvKind) []Detector {
+	if len(kinds) == 0 {
+		return []Detector{venvDetector{}}
+	}
+	var enabled []Detector
+	for _, d := range detectors {
+		for _, kind := range kinds {
+			if d.Kind() == kind {
+				enabled = append(enabled, d)
+				break
+			}
+		}
+	}
+	return enabled
+}
+
+// KindOfFolder guesses the kind of a folder from its name alone, for folders
+// that are no longer next to their project, such as in the trash. Venvs are
+// only recognised as .venv; ok is false for names no detector or artifact uses.
+func KindOfFolder(name string) (kind model.EnvKind, ok bool) {
+	if name == ".venv" {
+		return model.KindVenv, true
+	}
+	for _, d := range detectors {
+		if folder, isFolder := d.(folderDetector); isFolder && folder.name == name {
+			return folder.kind, true
+		}
+	}
+	if slices.Contains(artifactDirs, name) || slices.Contains(buildDirs, name) || strings.HasSuffix(name, ".egg-info") {
+		return model.KindArtifacts, true
+	}
+	return "", false
+}
+
+// pythonManifests are the files a venv can be recreated from
+var pythonManifests = []string{"pyproject.toml", "setup.py", "setup.cfg", "requirements.txt", "Pipfile", "tox.ini", "noxfile.py"}
+
+// venvDetector finds Python virtual environments
+type venvDetector struct{}
+
+func (venvDetector) Kind() model.EnvKind {
+	return model.KindVenv
+}
+
+func (venvDetector) Match(path string) bool {
+	return IsVenv(path)
+}
+
+func (venvDetector) Stat(ctx context.Context, repoPath, path string) (*model.VenvInfo, error) {
+	return CheckVenv(ctx, repoPath, path)
+}
+
+// Recreatable looks for a Python manifest next to the venv or at the root of
+// its repository; the project of a .tox or .nox environment is one level up
+func (venvDetector) Recreatable(repoPath, path string) bool {
+	project := filepath.Dir(path)
+	if envGroups[filepath.Base(project)] != "" {
+		project = filepath.Dir(project)
+	}
+	if hasAnyFile(project, pythonManifests) {
+		return true
+	}
+	return repoPath != "" && hasAnyFile(repoPath, pythonManifests)
+}
+
+// folderDetector finds dependency folders with a fixed name that sit next
+// to the manifest of their project, such as node_modules next to package.json
+type folderDetector struct {
+	kind        model.EnvKind
+	name        string   // Name of the folder
+	manifests   []string // Files next to the folder, one of which must exist
+	lockfiles   []string // Files pinning the exact dependencies, one of which must exist to recreate them; none if the folder is only build output
+	skipTracked bool     // Whether a folder is left out unless git confirms it tracks none of its files, as it is then not recreated but checked out
+}
+
+func (d folderDetector) Kind() model.EnvKind {
+	return d.kind
+}
+
+func (d folderDetector) Match(path string) bool {
+	return filepath.Base(path) == d.name && hasAnyFile(filepath.Dir(path), d.manifests)
+}
+
+func (d folderDetector) Stat(ctx context.Context, repoPath, path string) (*model.VenvInfo, error) {
+	if d.skipTracked && repoPath != "" && !isUntracked(repoPath, path) {
+		return nil, fmt.Errorf("%s may be tracked by git", path)
+	}
+	return checkFolder(ctx, d.kind, repoPath, path)
+}
+
+func (d folderDetector) Recreatable(repoPath, path string) bool {
+	return len(d.lockfiles) == 0 || hasAnyFile(filepath.Dir(path), d.lockfiles)
+}
+
+// knownLockfiles are the files that pin the exact dependencies of a project, in
+// any of the supported ecosystems
+var knownLockfiles = []string{
+	"uv.lock", "poetry.lock", "Pipfile.lock", "pdm.lock", "pylock.toml", "conda-lock.yml",
+	"package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml", "bun.lockb", "bun.lock",
+	"Cargo.lock", "gradle.lockfile", "go.sum", "composer.lock",
+}
+
+// Lockfiles returns the names of the lockfiles in the given project
+// directories, each name once
+func Lockfiles(dirs ...string) []string {
+	var found []string
+	for _, name := range knownLockfiles {
+		for _, dir := range dirs {
+			if dir != "" && hasAnyFile(dir, []string{name}) {
+				found = append(found, name)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// hasAnyFile reports whether dir holds a regular file with one of the names
+func hasAnyFile(dir string, names []string) bool {
+	for _, name := range names {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.Mode().IsRegular() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package scanner
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/raoulg/venvcleaner/model"
+)
+
+func TestParseKinds(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []model.EnvKind
+		wantErr bool
+	}{
+		{"venv", []model.EnvKind{model.KindVenv}, false},
+		{"venv,node,rust", []model.EnvKind{model.KindVenv, model.KindNode, model.KindRust}, false},
+		{" gradle , vendor ", []model.EnvKind{model.KindGradle, model.KindVendor}, false},
+		{"node,,", []model.EnvKind{model.KindNode}, false},
+		{"venv,python", nil, true},
+		{"poetry", nil, true},
+		{"", nil, true},
+		{" , ", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseKinds(tt.list)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseKinds(%q) error = %v, want error %v", tt.list, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseKinds(%q) = %v, want %v", tt.list, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package scanner
+
+import (
+	"flag"
+	"fmt"
+)
+
+// FlagSynopsis lists the flags RegisterFlags defines, for usage lines
+const FlagSynopsis = "[--max-depth N] [--include-non-repo] [--central-stores] [--conda] [--artifacts] [--kinds LIST]"
+
+// Flags are the scan options as flags on a flag.FlagSet
+type Flags struct {
+	maxDepth       *int
+	includeNonRepo *bool
+	centralStores  *bool
+	conda          *bool
+	artifacts      *bool
+	kinds          *string
+}
+
+// RegisterFlags defines the flags that set the scan options on fs
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	return &Flags{
+		maxDepth:       fs.Int("max-depth", DefaultMaxDepth, "how many levels below a repository root to look for venvs"),
+		includeNonRepo: fs.Bool("include-non-repo", false, "also find venvs outside git repositories"),
+		centralStores:  fs.Bool("central-stores", false, "also list the Poetry and Pipenv central venv stores"),
+		conda:          fs.Bool("conda", false, "also list conda and mamba environments"),
+		artifacts:      fs.Bool("artifacts", false, "also include Python caches and build artifacts in each repository"),
+		kinds:          fs.String("kinds", "venv", "comma-separated kinds of folder to look for: "+joinKinds(Kinds())),
+	}
+}
+
+// Options returns the options the flags were set to, once their FlagSet is
+// parsed. The error names the flag with an invalid value.
+func (f *Flags) Options() (Options, error) {
+	kinds, err := ParseKinds(*f.kinds)
+	if err != nil {
+		return Options{}, fmt.Errorf("--kinds: %w", err)
+	}
+	return Options{
+		MaxDepth:       *f.maxDepth,
+		IncludeNonRepo: *f.includeNonRepo,
+		CentralStores:  *f.centralStores,
+		Conda:          *f.conda,
+		Artifacts:      *f.artifacts,
+		Kinds:          kinds,
+	}, nil
+}
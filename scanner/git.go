@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isGitEntry reports whether the .git entry of repoPath marks a repository:
+// a directory, or a file with a "gitdir:" line as in worktrees and submodules
+func isGitEntry(repoPath string, entry os.DirEntry) bool {
+	if entry.IsDir() {
+		return true
+	}
+	if !entry.Type().IsRegular() {
+		return false
+	}
+	_, err := readGitdirFile(filepath.Join(repoPath, ".git"))
+	return err == nil
+}
+
+// readGitdirFile returns the git directory a .git file points to
+func readGitdirFile(path string) (string, error) {
+	line, err := readFirstLine(path)
+	if err != nil {
+		return "", err
+	}
+	gitDir, ok := strings.CutPrefix(line, "gitdir:")
+	if !ok {
+		return "", os.ErrInvalid
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	return filepath.Clean(gitDir), nil
+}
+
+// gitDirOf returns the git directory of the repository at repoPath
+func gitDirOf(repoPath string) (string, error) {
+	dotGit := filepath.Join(repoPath, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return dotGit, nil
+	}
+	return readGitdirFile(dotGit)
+}
+
+// inspectRepo returns the checked out branch of the repository at repoPath
+// (a short commit hash when detached) and, for a linked worktree, the path
+// of the main working tree. Missing details are returned empty.
+func inspectRepo(repoPath string) (branch, mainRepoPath string, worktree bool) {
+	gitDir, err := gitDirOf(repoPath)
+	if err != nil {
+		return "", "", false
+	}
+
+	if head, err := readFirstLine(filepath.Join(gitDir, "HEAD")); err == nil {
+		if ref, ok := strings.CutPrefix(head, "ref:"); ok {
+			branch = strings.TrimPrefix(strings.TrimSpace(ref), "refs/heads/")
+		} else if len(head) >= 7 {
+			branch = head[:7]
+		}
+	}
+
+	// Linked worktrees have a commondir file pointing at the main repository's git directory
+	commonDir, err := readFirstLine(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return branch, "", false
+	}
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
+	}
+	commonDir = filepath.Clean(commonDir)
+
+	// A bare repository has no working tree of its own
+	mainRepoPath = commonDir
+	if filepath.Base(commonDir) == ".git" {
+		mainRepoPath = filepath.Dir(commonDir)
+	}
+	return branch, mainRepoPath, true
+}
+
+// LastCommitTime returns the committer time of the commit checked out in the
+// repository at repoPath, as reported by git; a zero time means the
+// repository has no commits yet
+func LastCommitTime(repoPath string) (time.Time, error) {
+	// git log fails on an unborn branch; rev-parse tells it apart from other
+	// failures by exiting with 1
+	err := exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", "HEAD").Run()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && exit.ExitCode() == 1 {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	out, err := exec.Command("git", "-C", repoPath, "log", "-1", "--format=%ct").Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// isUntracked reports whether git confirms that it tracks no file below path
+// in the repository at repoPath. Any failure to ask, such as git not being
+// installed, counts as tracked, so committed files are never offered for deletion.
+func isUntracked(repoPath, path string) bool {
+	err := exec.Command("git", "-C", repoPath, "ls-files", "--error-unmatch", "--", path).Run()
+	var exit *exec.ExitError
+	return errors.As(err, &exit) && exit.ExitCode() == 1
+}
+
+// readFirstLine returns the first line of a small file, trimmed
+func readFirstLine(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	lines := bufio.NewScanner(f)
+	if !lines.Scan() {
+		if err := lines.Err(); err != nil {
+			return "", err
+		}
+		return "", nil
+	}
+	return strings.TrimSpace(lines.Text()), nil
+}
@@ -0,0 +1,101 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/raoulg/venvcleaner/model"
+)
+
+// Packages lists the distributions installed in a venv, largest first, from
+// the *.dist-info directories in its site-packages. Sizes add up the files
+// listed in each RECORD, so nothing but the metadata is read.
+func Packages(venvPath string) ([]model.Package, error) {
+	sitePackages, _ := filepath.Glob(filepath.Join(venvPath, "lib", "python*", "site-packages"))
+	windows, _ := filepath.Glob(filepath.Join(venvPath, "Lib", "site-packages"))
+
+	var packages []model.Package
+	for _, dir := range append(sitePackages, windows...) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && strings.HasSuffix(entry.Name(), ".dist-info") {
+				packages = append(packages, readDistInfo(filepath.Join(dir, entry.Name())))
+			}
+		}
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Size != packages[j].Size {
+			return packages[i].Size > packages[j].Size
+		}
+		return strings.ToLower(packages[i].Name) < strings.ToLower(packages[j].Name)
+	})
+	return packages, nil
+}
+
+// readDistInfo reads the name and version of one distribution from its
+// METADATA, falling back to the <name>-<version>.dist-info directory name,
+// and its size from RECORD
+func readDistInfo(distInfo string) model.Package {
+	var pkg model.Package
+	name, version, _ := strings.Cut(strings.TrimSuffix(filepath.Base(distInfo), ".dist-info"), "-")
+
+	if f, err := os.Open(filepath.Join(distInfo, "METADATA")); err == nil {
+		lines := bufio.NewScanner(f)
+		// The headers end at the first empty line, the description follows
+		for lines.Scan() && lines.Text() != "" {
+			if value, ok := strings.CutPrefix(lines.Text(), "Name:"); ok {
+				pkg.Name = strings.TrimSpace(value)
+			} else if value, ok := strings.CutPrefix(lines.Text(), "Version:"); ok {
+				pkg.Version = strings.TrimSpace(value)
+			}
+		}
+		f.Close()
+	}
+	if pkg.Name == "" {
+		pkg.Name = name
+	}
+	if pkg.Version == "" {
+		pkg.Version = version
+	}
+
+	pkg.Size = recordSize(filepath.Join(distInfo, "RECORD"))
+	return pkg
+}
+
+// recordSize adds up the sizes in a RECORD file, lines of path,hash,size.
+// Files without a recorded size, such as RECORD itself, are not counted.
+func recordSize(path string) int64 {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	records := csv.NewReader(f)
+	records.FieldsPerRecord = -1
+	records.LazyQuotes = true
+
+	var size int64
+	for {
+		record, err := records.Read()
+		if err != nil {
+			// io.EOF, or a malformed line we cannot recover from
+			return size
+		}
+		if len(record) < 3 {
+			continue
+		}
+		if n, err := strconv.ParseInt(record[2], 10, 64); err == nil {
+			size += n
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		record string
+		want   int64
+	}{
+		{
+			name:   "sizes added up",
+			record: "numpy/__init__.py,sha256=abc,1200\nnumpy/core.so,sha256=def,34000\n",
+			want:   35200,
+		},
+		{
+			name:   "files without a size",
+			record: "pkg/a.py,sha256=abc,100\npkg-1.0.dist-info/RECORD,,\npkg/__pycache__/a.cpython-312.pyc,,\n",
+			want:   100,
+		},
+		{
+			name:   "quoted path with a comma",
+			record: "\"pkg/a,b.py\",sha256=abc,50\npkg/c.py,sha256=def,25\n",
+			want:   75,
+		},
+		{
+			name:   "short and malformed lines",
+			record: "pkg/a.py\npkg/b.py,sha256=abc,notanumber\npkg/c.py,sha256=def,10\n",
+			want:   10,
+		},
+		{
+			name: "empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "RECORD")
+			if err := os.WriteFile(path, []byte(tt.record), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if got := recordSize(path); got != tt.want {
+				t.Errorf("recordSize = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if got := recordSize(filepath.Join(t.TempDir(), "missing")); got != 0 {
+		t.Errorf("recordSize of a missing file = %d, want 0", got)
+	}
+}
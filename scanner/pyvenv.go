@@ -0,0 +1,181 @@
+package scanner
+
+import (
+	"bufio"
+	"cmp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// IsVenv reports whether dir is a virtual environment: it holds a pyvenv.cfg
+// and an interpreter in bin/ (Unix) or Scripts/ (Windows). The interpreter is
+// usually a symlink, which only has to exist, not resolve.
+func IsVenv(dir string) bool {
+	cfg, err := os.Stat(filepath.Join(dir, "pyvenv.cfg"))
+	if err != nil || !cfg.Mode().IsRegular() {
+		return false
+	}
+
+	for _, python := range []string{filepath.Join("bin", "python"), filepath.Join("Scripts", "python.exe")} {
+		if _, err := os.Lstat(filepath.Join(dir, python)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// BrokenReason returns why a venv can no longer run, or "" if it looks
+// usable: the base interpreter recorded in pyvenv.cfg is gone, or the
+// bin/python symlink chain ends nowhere. Upgrading pyenv or Homebrew Pythons
+// typically leaves venvs like this behind.
+func BrokenReason(venvPath string, cfg map[string]string) string {
+	if home := cfg["home"]; home != "" {
+		if _, err := os.Stat(home); os.IsNotExist(err) {
+			return "base interpreter directory " + home + " no longer exists"
+		}
+	}
+	for _, key := range []string{"base-executable", "executable"} {
+		if executable := cfg[key]; executable != "" {
+			if _, err := os.Stat(executable); os.IsNotExist(err) {
+				return "base interpreter " + executable + " no longer exists"
+			}
+		}
+	}
+
+	for _, python := range []string{filepath.Join("bin", "python"), filepath.Join("Scripts", "python.exe")} {
+		link := filepath.Join(venvPath, python)
+		if _, err := os.Lstat(link); err != nil {
+			continue
+		}
+		// Stat follows the whole symlink chain
+		if _, err := os.Stat(link); err != nil {
+			return python + " points to a missing interpreter"
+		}
+		return ""
+	}
+	return ""
+}
+
+// ReadPyvenvCfg parses the "key = value" lines of a venv's pyvenv.cfg
+func ReadPyvenvCfg(venvPath string) (map[string]string, error) {
+	f, err := os.Open(filepath.Join(venvPath, "pyvenv.cfg"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cfg := make(map[string]string)
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		key, value, ok := strings.Cut(lines.Text(), "=")
+		if !ok {
+			continue
+		}
+		cfg[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+
+	return cfg, lines.Err()
+}
+
+// PythonVersion returns the interpreter version recorded in pyvenv.cfg, or "" if unknown.
+// The venv module writes "version", uv and virtualenv write "version_info",
+// virtualenv with the release level appended (3.12.4.final.0), which is dropped.
+func PythonVersion(cfg map[string]string) string {
+	version := cfg["version"]
+	if version == "" {
+		version = cfg["version_info"]
+	}
+	if parts := strings.Split(version, "."); len(parts) > 3 {
+		version = strings.Join(parts[:3], ".")
+	}
+	return version
+}
+
+// Creator returns the tool that created a venv according to its pyvenv.cfg:
+// "uv" and "virtualenv" record themselves, the venv module does not
+func Creator(cfg map[string]string) string {
+	switch {
+	case cfg["uv"] != "":
+		return "uv"
+	case cfg["virtualenv"] != "":
+		return "virtualenv"
+	case cfg["home"] != "":
+		return "venv"
+	}
+	return ""
+}
+
+// BaseInterpreter returns the interpreter a venv was created from: the
+// executable when pyvenv.cfg records it, otherwise the directory holding it
+func BaseInterpreter(cfg map[string]string) string {
+	for _, key := range []string{"base-executable", "executable", "home"} {
+		if path := cfg[key]; path != "" {
+			return path
+		}
+	}
+	return ""
+}
+
+// ComparePythonVersions compares two versions such as 3.9.18 and 3.12.1
+// numerically, part by part; a pre-release such as 3.12.0rc1 sorts before its
+// release, and unknown versions sort after known ones
+func ComparePythonVersions(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aSuffix := splitVersionPart(as[i])
+		bn, bSuffix := splitVersionPart(bs[i])
+		if an != bn {
+			return cmp.Compare(an, bn)
+		}
+		if aSuffix == bSuffix {
+			continue
+		}
+		// 0rc1 comes before 0, and a1 before b1 before rc1
+		switch {
+		case aSuffix == "":
+			return 1
+		case bSuffix == "":
+			return -1
+		}
+		aTag, aSerial := splitPreRelease(aSuffix)
+		bTag, bSerial := splitPreRelease(bSuffix)
+		if c := strings.Compare(aTag, bTag); c != 0 {
+			return c
+		}
+		return cmp.Compare(aSerial, bSerial)
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+// splitVersionPart splits one part of a version, such as 0rc1, into its
+// number and the text after it
+func splitVersionPart(part string) (int, string) {
+	digits := len(part) - len(strings.TrimLeft(part, "0123456789"))
+	n, _ := strconv.Atoi(part[:digits])
+	return n, part[digits:]
+}
+
+// splitPreRelease splits a pre-release suffix, such as rc1, into its tag and
+// serial number
+func splitPreRelease(suffix string) (string, int) {
+	tag := strings.TrimRight(suffix, "0123456789")
+	serial, _ := strconv.Atoi(suffix[len(tag):])
+	return tag, serial
+}
+
+// MatchPythonVersion reports whether version is want or a release of it,
+// so that 3.8 matches 3.8.10 but not 3.80
+func MatchPythonVersion(version, want string) bool {
+	return version == want || strings.HasPrefix(version, want+".")
+}
@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadPyvenvCfg(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		version     string
+		creator     string
+		interpreter string
+	}{
+		{
+			name:        "venv module",
+			cfg:         "home = /usr/bin\ninclude-system-site-packages = false\nversion = 3.12.4\nexecutable = /usr/bin/python3.12\n",
+			version:     "3.12.4",
+			creator:     "venv",
+			interpreter: "/usr/bin/python3.12",
+		},
+		{
+			name:        "uv",
+			cfg:         "home = /opt/python/bin\nimplementation = CPython\nuv = 0.4.18\nversion_info = 3.11.9\n",
+			version:     "3.11.9",
+			creator:     "uv",
+			interpreter: "/opt/python/bin",
+		},
+		{
+			name:        "virtualenv with release level",
+			cfg:         "home = /usr/bin\nvirtualenv = 20.26.3\nversion_info = 3.8.10.final.0\nbase-executable = /usr/bin/python3.8\n",
+			version:     "3.8.10",
+			creator:     "virtualenv",
+			interpreter: "/usr/bin/python3.8",
+		},
+		{
+			name:        "keys in any case, lines without =",
+			cfg:         "# comment\nHome=/usr/bin\nVERSION =3.10.2\n",
+			version:     "3.10.2",
+			creator:     "venv",
+			interpreter: "/usr/bin",
+		},
+		{
+			name: "empty",
+			cfg:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "pyvenv.cfg"), []byte(tt.cfg), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := ReadPyvenvCfg(dir)
+			if err != nil {
+				t.Fatalf("ReadPyvenvCfg: %v", err)
+			}
+			if got := PythonVersion(cfg); got != tt.version {
+				t.Errorf("PythonVersion = %q, want %q", got, tt.version)
+			}
+			if got := Creator(cfg); got != tt.creator {
+				t.Errorf("Creator = %q, want %q", got, tt.creator)
+			}
+			if got := BaseInterpreter(cfg); got != tt.interpreter {
+				t.Errorf("BaseInterpreter = %q, want %q", got, tt.interpreter)
+			}
+		})
+	}
+}
+
+func TestComparePythonVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"3.12.1", "3.12.1", 0},
+		{"3.9.18", "3.12.1", -1},
+		{"3.12.1", "3.9.18", 1},
+		{"3.12", "3.12.0", -1},
+		{"3.12.0rc1", "3.12.0", -1},
+		{"3.12.0", "3.12.0rc1", 1},
+		{"3.12.0a1", "3.12.0b1", -1},
+		{"3.12.0b2", "3.12.0rc1", -1},
+		{"3.12.0rc2", "3.12.0rc10", -1},
+		{"3.13.0a1", "3.12.4", 1},
+		{"", "3.8.10", 1},
+		{"3.8.10", "", -1},
+	}
+	for _, tt := range tests {
+		if got := ComparePythonVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("ComparePythonVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMatchPythonVersion(t *testing.T) {
+	tests := []struct {
+		version, want string
+		match         bool
+	}{
+		{"3.8.10", "3.8", true},
+		{"3.8.10", "3.8.10", true},
+		{"3.8", "3.8", true},
+		{"3.80.1", "3.8", false},
+		{"3.8.10", "3.8.1", false},
+		{"3.8.10", "3", true},
+		{"", "3.8", false},
+	}
+	for _, tt := range tests {
+		if got := MatchPythonVersion(tt.version, tt.want); got != tt.match {
+			t.Errorf("MatchPythonVersion(%q, %q) = %v, want %v", tt.version, tt.want, got, tt.match)
+		}
+	}
+}
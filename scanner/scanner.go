@@ -1,9 +1,13 @@
 package scanner
 
 import (
+	"context"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/raoulg/venvcleaner/model"
@@ -24,12 +28,14 @@ func FindGitRepos(rootPath string) ([]string, error) {
 			return filepath.SkipDir
 		}
 
-		// If we find a .git directory, record its parent as a repo
-		if d.IsDir() && d.Name() == ".git" {
+		// If we find a .git directory (or a worktree's .git file), record its parent as a repo
+		if d.Name() == ".git" && isGitEntry(filepath.Dir(path), d) {
 			repoPath := filepath.Dir(path)
 			repos = append(repos, repoPath)
 			// Don't descend into .git directories
-			return filepath.SkipDir
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
 		}
 
 		return nil
@@ -38,159 +44,293 @@ func FindGitRepos(rootPath string) ([]string, error) {
 	return repos, err
 }
 
-// CheckVenv checks if a repository has a .venv folder and returns info about it
-func CheckVenv(repoPath string) (*model.VenvInfo, error) {
-	venvPath := filepath.Join(repoPath, ".venv")
-
-	// Check if .venv exists and is a directory
-	info, err := os.Stat(venvPath)
+// CheckVenv returns info about the virtual environment at venvPath, which
+// belongs to the repository at repoPath, or to no repository if repoPath is ""
+func CheckVenv(ctx context.Context, repoPath, venvPath string) (*model.VenvInfo, error) {
+	venv, err := checkFolder(ctx, model.KindVenv, repoPath, venvPath)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil // No .venv, not an error
-		}
 		return nil, err
 	}
 
-	if !info.IsDir() {
-		return nil, nil // .venv exists but is not a directory
+	// Check for pyproject.toml in the repository, or next to a venv outside one
+	projectPath := repoPath
+	if projectPath == "" {
+		projectPath = filepath.Dir(venvPath)
 	}
-
-	// Check for pyproject.toml
-	pyprojectPath := filepath.Join(repoPath, "pyproject.toml")
+	pyprojectPath := filepath.Join(projectPath, "pyproject.toml")
 	_, err = os.Stat(pyprojectPath)
-	hasPyproject := err == nil
+	venv.HasPyproject = err == nil
+
+	// What the venv is: which Python, made by which tool
+	if cfg, err := ReadPyvenvCfg(venvPath); err == nil {
+		venv.PythonVersion = PythonVersion(cfg)
+		venv.Creator = Creator(cfg)
+		venv.BaseInterpreter = BaseInterpreter(cfg)
+		venv.SystemSitePackages = strings.EqualFold(cfg["include-system-site-packages"], "true")
+		venv.BrokenReason = BrokenReason(venvPath, cfg)
+		venv.Broken = venv.BrokenReason != ""
+	}
 
-	// Get venv size
-	size, err := GetVenvSize(venvPath)
+	return venv, nil
+}
+
+// checkFolder returns the statistics of a dependency folder of any kind, and
+// the state of the repository it belongs to
+func checkFolder(ctx context.Context, kind model.EnvKind, repoPath, path string) (*model.VenvInfo, error) {
+	info, err := os.Stat(path)
 	if err != nil {
-		size = 0 // If we can't calculate size, default to 0
+		return nil, err
 	}
 
-	// Get last modified time
-	lastModified, err := GetLastModified(venvPath)
+	// Gather size, modification time and counts in a single walk
+	stats, err := StatVenv(ctx, path)
 	if err != nil {
-		lastModified = info.ModTime() // Fallback to venv dir modification time
+		return nil, err
+	}
+	if stats.LastModified.IsZero() {
+		stats.LastModified = info.ModTime() // Fallback to the folder's modification time
 	}
 
-	return &model.VenvInfo{
+	folder := &model.VenvInfo{
+		Kind:         kind,
 		RepoPath:     repoPath,
-		VenvPath:     venvPath,
-		HasPyproject: hasPyproject,
-		LastModified: lastModified,
-		Size:         size,
+		VenvPath:     path,
+		LastModified: stats.LastModified,
+		Size:         stats.Size,
+		DiskUsage:    stats.DiskUsage,
+		Reclaimable:  stats.Reclaimable,
+		SharedFiles:  stats.SharedFiles,
+		FileCount:    stats.FileCount,
+		DirCount:     stats.DirCount,
+		LargestDirs:  stats.LargestDirs,
 		Selected:     false,
-	}, nil
+	}
+	if repoPath != "" {
+		folder.Branch, folder.MainRepoPath, folder.Worktree = inspectRepo(repoPath)
+	}
+
+	return folder, nil
 }
 
-// GetVenvSize calculates the total size of a .venv directory
-func GetVenvSize(venvPath string) (int64, error) {
-	var size int64
+// largestDirsCount is how many directories VenvStats.LargestDirs keeps
+const largestDirsCount = 5
+
+// VenvStats are the statistics StatVenv gathers about a venv
+type VenvStats struct {
+	Size         int64                             // Total size of all files in bytes
+	DiskUsage    int64                             // Bytes allocated on disk, counting hard-linked files once
+	Reclaimable  int64                             // Bytes freed by deleting the venv
+	SharedFiles  map[model.FileID]model.SharedFile // Files with hard links, by identity
+	LastModified time.Time                         // Most recent modification time of any entry
+	FileCount    int                               // Number of files
+	DirCount     int                               // Number of directories, including the venv itself
+	LargestDirs  []model.DirSize                   // Biggest packages or top-level folders, largest first
+}
+
+// StatVenv walks a venv once and gathers its size, newest modification time,
+// file and directory counts and largest subdirectories. Disk usage counts
+// allocated blocks, so sparse files count for what they occupy, and every
+// hard-linked file once; files with links outside the venv are not
+// reclaimable. Installed packages (site-packages/<name>) are measured
+// individually, everything else by its top-level folder. Unreadable entries
+// are skipped; only cancellation is an error.
+func StatVenv(ctx context.Context, venvPath string) (VenvStats, error) {
+	var stats VenvStats
+	dirSizes := make(map[string]int64)
 
 	err := filepath.WalkDir(venvPath, func(path string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if err != nil {
 			// Skip files/dirs we can't access
 			return nil
 		}
 
-		if !d.IsDir() {
-			info, err := d.Info()
-			if err == nil {
-				size += info.Size()
-			}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if info.ModTime().After(stats.LastModified) {
+			stats.LastModified = info.ModTime()
 		}
 
-		return nil
-	})
-
-	return size, err
-}
-
-// GetLastModified finds the most recently modified file in a .venv directory
-func GetLastModified(venvPath string) (time.Time, error) {
-	var lastModified time.Time
+		usage, id, links := diskUsage(info)
 
-	err := filepath.WalkDir(venvPath, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			// Skip files/dirs we can't access
+		if d.IsDir() {
+			stats.DirCount++
+			stats.DiskUsage += usage
+			stats.Reclaimable += usage
 			return nil
 		}
 
-		info, err := d.Info()
-		if err == nil {
-			if info.ModTime().After(lastModified) {
-				lastModified = info.ModTime()
+		stats.FileCount++
+		stats.Size += info.Size()
+
+		if links > 1 {
+			if stats.SharedFiles == nil {
+				stats.SharedFiles = make(map[model.FileID]model.SharedFile)
+			}
+			file, seen := stats.SharedFiles[id]
+			if !seen {
+				file = model.SharedFile{Usage: usage, Links: links}
+				stats.DiskUsage += usage
+			}
+			file.Seen++
+			stats.SharedFiles[id] = file
+		} else {
+			stats.DiskUsage += usage
+			stats.Reclaimable += usage
+		}
+		if len(path) > len(venvPath) {
+			if bucket := sizeBucket(path[len(venvPath)+1:]); bucket != "" {
+				dirSizes[bucket] += info.Size()
 			}
 		}
 
 		return nil
 	})
 
-	return lastModified, err
+	// Hard-linked files are only freed once every link is inside the venv
+	for _, file := range stats.SharedFiles {
+		if file.Seen >= file.Links {
+			stats.Reclaimable += file.Usage
+		}
+	}
+
+	for path, size := range dirSizes {
+		stats.LargestDirs = append(stats.LargestDirs, model.DirSize{Path: path, Size: size})
+	}
+	sort.Slice(stats.LargestDirs, func(i, j int) bool {
+		if stats.LargestDirs[i].Size != stats.LargestDirs[j].Size {
+			return stats.LargestDirs[i].Size > stats.LargestDirs[j].Size
+		}
+		return stats.LargestDirs[i].Path < stats.LargestDirs[j].Path
+	})
+	if len(stats.LargestDirs) > largestDirsCount {
+		stats.LargestDirs = stats.LargestDirs[:largestDirsCount]
+	}
+
+	return stats, err
 }
 
-// ScanForVenvs scans a root path for git repos with .venv folders
-// Returns two channels: one for results and one for progress updates
-func ScanForVenvs(rootPath string) (<-chan *model.VenvInfo, <-chan model.ScanProgress) {
-	results := make(chan *model.VenvInfo)
-	progress := make(chan model.ScanProgress)
+// sizeBucket returns the directory a file's size is attributed to: the
+// package folder for files inside site-packages/<name>/, the top-level folder
+// for anything else, and "" for files that belong to no subdirectory
+func sizeBucket(rel string) string {
+	parts := strings.SplitN(filepath.ToSlash(rel), "/", 5)
+
+	// lib/pythonX.Y/site-packages/<name>/... on Unix, Lib/site-packages/<name>/... on Windows
+	for i := 0; i < len(parts)-1 && i < 3; i++ {
+		if parts[i] == "site-packages" {
+			if len(parts) > i+2 {
+				return filepath.FromSlash(strings.Join(parts[:i+2], "/"))
+			}
+			return "" // A module file directly in site-packages
+		}
+	}
 
-	go func() {
-		defer close(results)
-		defer close(progress)
+	if len(parts) > 1 {
+		return parts[0]
+	}
+	return ""
+}
 
-		foldersScanned := 0
-		reposFound := 0
+// GetVenvSize calculates the total size of a .venv directory
+func GetVenvSize(venvPath string) (int64, error) {
+	stats, err := StatVenv(context.Background(), venvPath)
+	return stats.Size, err
+}
 
-		err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
-			if err != nil {
-				// Skip directories we can't access
-				return nil
-			}
+// GetLastModified finds the most recently modified file in a .venv directory
+func GetLastModified(venvPath string) (time.Time, error) {
+	stats, err := StatVenv(context.Background(), venvPath)
+	return stats.LastModified, err
+}
 
-			// Skip hidden directories except .git
-			if d.IsDir() && len(d.Name()) > 0 && d.Name()[0] == '.' && d.Name() != ".git" {
-				return filepath.SkipDir
-			}
+// DefaultMaxDepth is how deep inside a repository venvs are looked for when
+// Options.MaxDepth is not set; 1 finds <repo>/.venv, 2 <repo>/backend/.venv
+const DefaultMaxDepth = 3
+
+// Options configures a scan. The zero value scans every git repository below
+// the root for virtual environments up to DefaultMaxDepth levels deep.
+type Options struct {
+	Workers        int             // Directories read concurrently; below 1 means DefaultWorkers()
+	MaxDepth       int             // Levels below a repository root to look for venvs; below 1 means DefaultMaxDepth
+	IncludeNonRepo bool            // Also report venvs outside any git repository, at any depth
+	CentralStores  bool            // Also report the venvs in the Poetry and Pipenv central stores
+	Conda          bool            // Also report conda and mamba environments
+	Artifacts      bool            // Also collect the caches and build outputs of each repository
+	Kinds          []model.EnvKind // Kinds of dependency folder to look for, see Kinds(); empty means only venvs
+}
 
-			// Send progress update for each directory we scan
-			if d.IsDir() {
-				foldersScanned++
-				progress <- model.ScanProgress{
-					CurrentPath:    path,
-					ReposFound:     reposFound,
-					FoldersScanned: foldersScanned,
-				}
-			}
+// DefaultWorkers is the number of scan workers used when Options.Workers is not set.
+// Scanning waits on the disk far more than on the CPU, so use at least a few.
+func DefaultWorkers() int {
+	return max(4, runtime.NumCPU())
+}
 
-			// If we find a .git directory, check the parent for .venv
-			if d.IsDir() && d.Name() == ".git" {
-				repoPath := filepath.Dir(path)
-
-				venvInfo, err := CheckVenv(repoPath)
-				if err == nil && venvInfo != nil {
-					// Found a repo with .venv
-					reposFound++
-					results <- venvInfo
-
-					// Send updated progress
-					progress <- model.ScanProgress{
-						CurrentPath:    repoPath,
-						ReposFound:     reposFound,
-						FoldersScanned: foldersScanned,
-					}
-				}
-
-				// Don't descend into .git directories
-				return filepath.SkipDir
-			}
+// ScanForVenvs scans a root path for virtual environments, or the kinds of
+// dependency folder in opts.Kinds, inside git repos, and outside them too
+// with opts.IncludeNonRepo. With opts.CentralStores the Poetry and Pipenv
+// stores, and with opts.Conda the conda environments, follow once the tree
+// is done.
+// Returns three channels: one for results, one for progress updates and one
+// that receives the error that ended the scan (nil when it completed) after
+// the other two are closed. Cancelling ctx stops the walk promptly; the scan
+// then ends with ctx.Err().
+//
+// Directories are read and venvs measured by a pool of opts.Workers
+// goroutines, but results are always sent in the order a sequential
+// filepath.WalkDir would find them.
+func ScanForVenvs(ctx context.Context, rootPath string, opts Options) (<-chan *model.VenvInfo, <-chan model.ScanProgress, <-chan error) {
+	results := make(chan *model.VenvInfo)
+	progress := make(chan model.ScanProgress)
+	errc := make(chan error, 1)
 
-			return nil
-		})
+	go func() {
+		walkCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		w := &walker{ctx: walkCtx, opts: opts, queue: newWorkQueue(), detectors: enabledDetectors(opts.Kinds)}
+		root := newDirNode(rootPath)
+		workers := w.run(root)
+
+		stopProgress := make(chan struct{})
+		progressDone := make(chan struct{})
+		go func() {
+			defer close(progressDone)
+			w.reportProgress(progress, stopProgress)
+		}()
+
+		err := w.emit(root, nil, results)
+		if err == nil && root.err != nil {
+			// Nothing to scan at all
+			err = root.err
+		}
+		if err == nil {
+			err = w.scanStores(results)
+		}
 
-		if err != nil {
-			return
+		// Make sure all filesystem work has stopped before reporting the end
+		cancel()
+		workers.Wait()
+		close(stopProgress)
+		<-progressDone
+
+		// A complete scan ends with its final totals
+		if err == nil {
+			select {
+			case progress <- w.stats.snapshot():
+			case <-ctx.Done():
+			}
 		}
+
+		close(results)
+		close(progress)
+		errc <- err
+		close(errc)
 	}()
 
-	return results, progress
+	return results, progress, errc
 }
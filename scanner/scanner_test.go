@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/raoulg/venvcleaner/model"
+)
+
+func TestStatVenvHardLinks(t *testing.T) {
+	root := t.TempDir()
+	a := filepath.Join(root, "a", ".venv")
+	b := filepath.Join(root, "b", ".venv")
+	cache := filepath.Join(root, "cache")
+	for _, dir := range []string{a, b, cache} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write := func(path string) {
+		t.Helper()
+		if err := os.WriteFile(path, make([]byte, 10000), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := func(from, to string) {
+		t.Helper()
+		if err := os.Link(from, to); err != nil {
+			t.Skipf("hard links unsupported: %v", err)
+		}
+	}
+	usage := func(path string) int64 {
+		t.Helper()
+		info, err := os.Lstat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		usage, _, _ := diskUsage(info)
+		return usage
+	}
+
+	write(filepath.Join(a, "own"))
+	write(filepath.Join(a, "inner")) // Linked twice within a
+	link(filepath.Join(a, "inner"), filepath.Join(a, "inner2"))
+	write(filepath.Join(cache, "cached")) // Also linked from a package cache
+	link(filepath.Join(cache, "cached"), filepath.Join(a, "cached"))
+	write(filepath.Join(a, "both")) // Linked from both venvs
+	link(filepath.Join(a, "both"), filepath.Join(b, "both"))
+
+	info, err := os.Lstat(filepath.Join(a, "inner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, links := diskUsage(info); links < 2 {
+		t.Skip("hard link counts unavailable on this platform")
+	}
+
+	statsA, err := StatVenv(context.Background(), a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statsB, err := StatVenv(context.Background(), b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := int64(5 * 10000); statsA.Size != want {
+		t.Errorf("Size = %d, want %d counting every link", statsA.Size, want)
+	}
+	dirs := usage(a)
+	files := usage(filepath.Join(a, "own")) + usage(filepath.Join(a, "inner")) +
+		usage(filepath.Join(a, "cached")) + usage(filepath.Join(a, "both"))
+	if want := dirs + files; statsA.DiskUsage != want {
+		t.Errorf("DiskUsage = %d, want %d counting every file once", statsA.DiskUsage, want)
+	}
+	unshared := dirs + usage(filepath.Join(a, "own")) + usage(filepath.Join(a, "inner"))
+	if statsA.Reclaimable != unshared {
+		t.Errorf("Reclaimable = %d, want %d without files linked from elsewhere", statsA.Reclaimable, unshared)
+	}
+
+	// Deleting both venvs also frees the file they share, not the cached one
+	venvs := []model.VenvInfo{
+		{Reclaimable: statsA.Reclaimable, SharedFiles: statsA.SharedFiles},
+		{Reclaimable: statsB.Reclaimable, SharedFiles: statsB.SharedFiles},
+	}
+	want := statsA.Reclaimable + statsB.Reclaimable + usage(filepath.Join(a, "both"))
+	if got := model.ReclaimableSize(venvs); got != want {
+		t.Errorf("ReclaimableSize(a, b) = %d, want %d", got, want)
+	}
+	if got := model.ReclaimableSize(venvs[:1]); got != statsA.Reclaimable {
+		t.Errorf("ReclaimableSize(a) = %d, want %d", got, statsA.Reclaimable)
+	}
+}
@@ -0,0 +1,220 @@
+package scanner
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/raoulg/venvcleaner/model"
+)
+
+// PoetryVenvsDir returns the directory where Poetry keeps its virtualenvs
+func PoetryVenvsDir() (string, error) {
+	if dir := os.Getenv("POETRY_VIRTUALENVS_PATH"); dir != "" {
+		return dir, nil
+	}
+	if dir := os.Getenv("POETRY_CACHE_DIR"); dir != "" {
+		return filepath.Join(dir, "virtualenvs"), nil
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(cache, "pypoetry", "Cache", "virtualenvs"), nil
+	}
+	return filepath.Join(cache, "pypoetry", "virtualenvs"), nil
+}
+
+// PipenvVenvsDir returns the directory where Pipenv keeps its virtualenvs
+func PipenvVenvsDir() (string, error) {
+	if dir := os.Getenv("WORKON_HOME"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "virtualenvs"), nil
+}
+
+// poetryHash returns the hash Poetry puts in the names of a project's
+// virtualenvs, <name>-<hash>-py<version>
+func poetryHash(projectPath string) string {
+	sum := sha256.Sum256([]byte(projectPath))
+	return base64.URLEncoding.EncodeToString(sum[:])[:8]
+}
+
+// poetryNameHash extracts the project hash from a Poetry virtualenv name
+func poetryNameHash(name string) (string, bool) {
+	i := strings.LastIndex(name, "-py")
+	if i < 9 || name[i-9] != '-' {
+		return "", false
+	}
+	return name[i-8 : i], true
+}
+
+// storeProject is the project a centrally stored venv belongs to
+type storeProject struct {
+	path    string // Project directory, "" if unknown
+	missing bool   // The project directory no longer exists
+}
+
+// poetryProject finds the project of a Poetry virtualenv. Its name only holds
+// a hash of the project path, which is matched against the projects seen
+// during the scan and against the paths of editable installs in the venv.
+// A hash matching neither leaves the project unknown; it may well live
+// outside the scanned tree.
+func poetryProject(venvPath string, hashes map[string]string) storeProject {
+	hash, ok := poetryNameHash(filepath.Base(venvPath))
+	if !ok {
+		return storeProject{}
+	}
+	if path, ok := hashes[hash]; ok {
+		return storeProject{path: path}
+	}
+
+	// Poetry installs the project itself in editable mode, as a .pth file
+	// pointing into the project; its directory may be gone by now
+	for _, installed := range editablePaths(venvPath) {
+		for dir := installed; ; dir = filepath.Dir(dir) {
+			if poetryHash(dir) == hash {
+				_, err := os.Stat(dir)
+				return storeProject{path: dir, missing: os.IsNotExist(err)}
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+	return storeProject{}
+}
+
+// editablePaths returns the absolute paths listed in the .pth files of a venv
+func editablePaths(venvPath string) []string {
+	var paths []string
+	sitePackages, _ := filepath.Glob(filepath.Join(venvPath, "lib", "python*", "site-packages", "*.pth"))
+	windows, _ := filepath.Glob(filepath.Join(venvPath, "Lib", "site-packages", "*.pth"))
+
+	for _, pth := range append(sitePackages, windows...) {
+		f, err := os.Open(pth)
+		if err != nil {
+			continue
+		}
+		lines := bufio.NewScanner(f)
+		for lines.Scan() {
+			if line := strings.TrimSpace(lines.Text()); filepath.IsAbs(line) {
+				paths = append(paths, filepath.Clean(line))
+			}
+		}
+		f.Close()
+	}
+	return paths
+}
+
+// pipenvProject reads the project of a Pipenv virtualenv from its .project file
+func pipenvProject(venvPath string) storeProject {
+	path, err := readFirstLine(filepath.Join(venvPath, ".project"))
+	if err != nil || path == "" {
+		return storeProject{}
+	}
+	_, err = os.Stat(path)
+	return storeProject{path: path, missing: os.IsNotExist(err)}
+}
+
+// envStore is a place outside the scanned tree that holds environments of one kind
+type envStore struct {
+	kind    model.EnvKind
+	envs    func() []string                   // Paths of the environments in the store
+	project func(envPath string) storeProject // Project an environment belongs to, if known
+}
+
+// storeVenvs lists the virtualenvs directly inside the directory returned by dir
+func storeVenvs(dir func() (string, error)) func() []string {
+	return func() []string {
+		path, err := dir()
+		if err != nil {
+			return nil
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			// Not installed or never used
+			return nil
+		}
+
+		var venvs []string
+		for _, entry := range entries {
+			venvPath := filepath.Join(path, entry.Name())
+			if entry.IsDir() && IsVenv(venvPath) {
+				venvs = append(venvs, venvPath)
+			}
+		}
+		return venvs
+	}
+}
+
+// stores returns the stores Options asks for
+func (w *walker) stores() []envStore {
+	var stores []envStore
+
+	if w.opts.CentralStores {
+		// Poetry hashes the resolved project path
+		hashes := make(map[string]string)
+		for _, project := range w.projects.list() {
+			if resolved, err := filepath.EvalSymlinks(project); err == nil {
+				hashes[poetryHash(resolved)] = project
+			}
+			hashes[poetryHash(project)] = project
+		}
+
+		stores = append(stores,
+			envStore{model.KindPoetry, storeVenvs(PoetryVenvsDir), func(venvPath string) storeProject { return poetryProject(venvPath, hashes) }},
+			envStore{model.KindPipenv, storeVenvs(PipenvVenvsDir), pipenvProject},
+		)
+	}
+
+	if w.opts.Conda {
+		// Conda environments are not tied to a project
+		stores = append(stores, envStore{model.KindConda, CondaEnvs, func(string) storeProject { return storeProject{} }})
+	}
+
+	return stores
+}
+
+// scanStores reports the environments in the stores Options asks for,
+// mapped back to their projects where possible
+func (w *walker) scanStores(results chan<- *model.VenvInfo) error {
+	for _, store := range w.stores() {
+		for _, envPath := range store.envs() {
+			if err := w.ctx.Err(); err != nil {
+				return err
+			}
+			w.stats.currentPath.Store(envPath)
+
+			project := store.project(envPath)
+			venvInfo, err := CheckVenv(w.ctx, project.path, envPath)
+			if err != nil || venvInfo == nil {
+				continue
+			}
+			venvInfo.Kind = store.kind
+			venvInfo.ProjectMissing = project.missing
+			venvInfo.Recreatable = project.path != "" && !project.missing && hasAnyFile(project.path, pythonManifests)
+
+			// Conda records every install, update and removal in conda-meta/history
+			if store.kind == model.KindConda {
+				if info, err := os.Stat(filepath.Join(envPath, "conda-meta", "history")); err == nil {
+					venvInfo.LastModified = info.ModTime()
+				}
+			}
+
+			if err := w.send(venvInfo, results); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
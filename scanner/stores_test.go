@@ -0,0 +1,42 @@
+package scanner
+
+import "testing"
+
+func TestPoetryHash(t *testing.T) {
+	// Expected values from Poetry's own scheme:
+	// base64.urlsafe_b64encode(hashlib.sha256(path.encode()).digest())[:8]
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/home/user/project", "na0eTgiw"},
+		{"/tmp/pv/src", "7_YZ2pVE"},
+	}
+	for _, tt := range tests {
+		if got := poetryHash(tt.path); got != tt.want {
+			t.Errorf("poetryHash(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPoetryNameHash(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"project-na0eTgiw-py3.12", "na0eTgiw", true},
+		{"my-project-7_YZ2pVE-py3.9", "7_YZ2pVE", true},
+		{"x-AbCd-EfG-py3.12", "AbCd-EfG", true},
+		{"project-py3.12", "", false},
+		{"project-na0eTgiw", "", false},
+		{"projectna0eTgiw-py3.12", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := poetryNameHash(tt.name)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("poetryNameHash(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
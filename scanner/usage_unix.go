@@ -0,0 +1,20 @@
+//go:build unix
+
+package scanner
+
+import (
+	"io/fs"
+	"syscall"
+
+	"github.com/raoulg/venvcleaner/model"
+)
+
+// diskUsage returns the bytes allocated on disk for a file, which are fewer
+// than its size for sparse files, along with its identity and hard link count
+func diskUsage(info fs.FileInfo) (usage int64, id model.FileID, links uint64) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.Size(), model.FileID{}, 1
+	}
+	return int64(stat.Blocks) * 512, model.FileID{Dev: uint64(stat.Dev), Ino: uint64(stat.Ino)}, uint64(stat.Nlink)
+}
@@ -0,0 +1,379 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/raoulg/venvcleaner/model"
+)
+
+// progressInterval is how often scan progress is reported. Workers only
+// update counters; a single goroutine turns them into ScanProgress updates.
+const progressInterval = 50 * time.Millisecond
+
+// envGroups are the hidden directories that test runners keep one venv per
+// environment in, with the kind of those venvs
+var envGroups = map[string]model.EnvKind{
+	".tox": model.KindTox,
+	".nox": model.KindNox,
+}
+
+// dirNode is a directory in the scan tree. Workers fill in children and venv
+// and then close ready. The emitter visits nodes in the same lexical,
+// depth-first order as filepath.WalkDir, waiting on ready for each, so the
+// results come out in a deterministic order however the workers race.
+type dirNode struct {
+	path      string
+	repo      string // Nearest enclosing repository root, "" outside any repository
+	depth     int    // Levels below repo
+	ready     chan struct{}
+	children  []*dirNode
+	venv      *model.VenvInfo
+	artifacts []model.Artifact // Artifact directories directly below, with Options.Artifacts
+	err       error            // Error reading the directory, only fatal for the root
+}
+
+func newDirNode(path string) *dirNode {
+	return &dirNode{path: path, ready: make(chan struct{})}
+}
+
+// child returns a node for the subdirectory name, in the same repository
+func (n *dirNode) child(name string) *dirNode {
+	child := newDirNode(filepath.Join(n.path, name))
+	if n.repo != "" {
+		child.repo, child.depth = n.repo, n.depth+1
+	}
+	return child
+}
+
+// workQueue is an unbounded LIFO queue of directories waiting to be read.
+// LIFO keeps the workers close to the emitter's depth-first position, which
+// keeps the results streaming and the tree in memory small.
+type workQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	nodes   []*dirNode
+	pending int // Nodes queued or being processed
+	closed  bool
+}
+
+func newWorkQueue() *workQueue {
+	q := &workQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// push queues nodes; they are popped in the order given
+func (q *workQueue) push(nodes ...*dirNode) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i := len(nodes) - 1; i >= 0; i-- {
+		q.nodes = append(q.nodes, nodes[i])
+	}
+	q.pending += len(nodes)
+	q.cond.Broadcast()
+}
+
+// pop waits for the next node; ok is false once the queue is closed
+func (q *workQueue) pop() (node *dirNode, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.nodes) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return nil, false
+	}
+	node = q.nodes[len(q.nodes)-1]
+	q.nodes = q.nodes[:len(q.nodes)-1]
+	return node, true
+}
+
+// done marks a popped node as processed and closes the queue when nothing is left
+func (q *workQueue) done() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending--
+	if q.pending == 0 {
+		q.closed = true
+		q.cond.Broadcast()
+	}
+}
+
+// close wakes up and stops all workers
+func (q *workQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.cond.Broadcast()
+}
+
+// scanStats are the counters behind ScanProgress, shared by all workers
+type scanStats struct {
+	foldersScanned atomic.Int64
+	reposFound     atomic.Int64
+	currentPath    atomic.Value // string
+}
+
+func (s *scanStats) snapshot() model.ScanProgress {
+	current, _ := s.currentPath.Load().(string)
+	return model.ScanProgress{
+		CurrentPath:    current,
+		ReposFound:     int(s.reposFound.Load()),
+		FoldersScanned: int(s.foldersScanned.Load()),
+	}
+}
+
+// projectSet collects the directories holding a pyproject.toml, shared by all workers
+type projectSet struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (p *projectSet) add(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paths = append(p.paths, path)
+}
+
+func (p *projectSet) list() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.paths)
+}
+
+// walker scans a tree with a bounded pool of workers
+type walker struct {
+	ctx       context.Context
+	opts      Options
+	queue     *workQueue
+	stats     scanStats
+	projects  projectSet // Only collected for Options.CentralStores
+	detectors []Detector // The kinds of folder Options asks for
+}
+
+// maxDepth returns the configured venv search depth inside repositories
+func (w *walker) maxDepth() int {
+	if w.opts.MaxDepth < 1 {
+		return DefaultMaxDepth
+	}
+	return w.opts.MaxDepth
+}
+
+// detect returns the detector matching the directory at path, or nil if none does
+func (w *walker) detect(path string) Detector {
+	for _, d := range w.detectors {
+		if d.Match(path) {
+			return d
+		}
+	}
+	return nil
+}
+
+// wantsVenv reports whether a venv at node would be reported: inside a
+// repository up to the maximum depth, and outside one only if asked for
+func (w *walker) wantsVenv(node *dirNode) bool {
+	if node.repo == "" {
+		return w.opts.IncludeNonRepo
+	}
+	return node.depth > 0 && node.depth <= w.maxDepth()
+}
+
+// process reads one directory: it records it if it is a venv worth
+// reporting, and otherwise queues the subdirectories worth descending into
+func (w *walker) process(node *dirNode) {
+	defer close(node.ready)
+
+	w.stats.foldersScanned.Add(1)
+	w.stats.currentPath.Store(node.path)
+
+	entries, err := os.ReadDir(node.path)
+	if err != nil {
+		// Skip directories we can't access
+		node.err = err
+		return
+	}
+
+	// A .git directory, or a .git file in worktrees and submodules, makes
+	// this the repository that venvs below belong to
+	for _, entry := range entries {
+		if entry.Name() == ".git" && isGitEntry(node.path, entry) {
+			node.repo, node.depth = node.path, 0
+		}
+	}
+
+	// Projects are matched with the venvs Poetry keeps in its central store
+	if w.opts.CentralStores && slices.ContainsFunc(entries, func(entry os.DirEntry) bool {
+		return entry.Name() == "pyproject.toml" && !entry.IsDir()
+	}) {
+		w.projects.add(node.path)
+	}
+
+	// A venv or other dependency folder is recorded as a whole; there is
+	// nothing to find inside it
+	if w.wantsVenv(node) {
+		if d := w.detect(node.path); d != nil {
+			venvInfo, err := d.Stat(w.ctx, node.repo, node.path)
+			if err == nil {
+				venvInfo.Recreatable = d.Recreatable(node.repo, node.path)
+				if group := filepath.Dir(node.path); d.Kind() == model.KindVenv && envGroups[filepath.Base(group)] != "" {
+					venvInfo.Kind = envGroups[filepath.Base(group)]
+					venvInfo.Group = group
+				}
+				node.venv = venvInfo
+			}
+			return
+		}
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if name == ".git" {
+			continue
+		}
+		child := node.child(name)
+
+		// Artifacts are measured as a whole instead of descended into
+		if w.opts.Artifacts && child.repo != "" && isArtifact(child.repo, child.path, entries) {
+			node.artifacts = append(node.artifacts, w.measureArtifact(child.path))
+			continue
+		}
+
+		// Skip hidden directories, except venvs such as .venv, the .tox and
+		// .nox directories holding them and folders such as .gradle
+		if name[0] == '.' && !(w.wantsVenv(child) && (envGroups[name] != "" || w.detect(child.path) != nil)) {
+			continue
+		}
+
+		node.children = append(node.children, child)
+	}
+
+	w.queue.push(node.children...)
+}
+
+// run starts the workers on the tree below root; wait for the returned group
+// to be sure no worker touches the filesystem anymore
+func (w *walker) run(root *dirNode) *sync.WaitGroup {
+	workers := w.opts.Workers
+	if workers < 1 {
+		workers = DefaultWorkers()
+	}
+
+	var wg sync.WaitGroup
+	w.queue.push(root)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				node, ok := w.queue.pop()
+				if !ok {
+					return
+				}
+				if w.ctx.Err() == nil {
+					w.process(node)
+				}
+				w.queue.done()
+			}
+		}()
+	}
+
+	// Stop the workers as soon as the scan is cancelled
+	stop := context.AfterFunc(w.ctx, w.queue.close)
+	go func() {
+		wg.Wait()
+		stop()
+	}()
+
+	return &wg
+}
+
+// emit sends the venvs below node in depth-first order as their directories
+// are read. With Options.Artifacts the artifacts of each repository are
+// collected in found, and sent on an entry of their own once the repository
+// is done.
+func (w *walker) emit(node *dirNode, found *repoArtifacts, results chan<- *model.VenvInfo) error {
+	select {
+	case <-node.ready:
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+
+	var own *repoArtifacts
+	if w.opts.Artifacts && node.repo == node.path {
+		own = &repoArtifacts{path: node.path}
+		found = own
+	}
+
+	if found != nil {
+		found.artifacts = append(found.artifacts, node.artifacts...)
+	}
+	if node.venv != nil {
+		if err := w.send(node.venv, results); err != nil {
+			return err
+		}
+	}
+
+	// The emitter is the last to need the subtree, let it be collected as we go
+	children := node.children
+	node.children = nil
+	for _, child := range children {
+		if err := w.emit(child, found, results); err != nil {
+			return err
+		}
+	}
+
+	if own != nil && len(own.artifacts) > 0 {
+		return w.send(own.entry(), results)
+	}
+	return nil
+}
+
+// send reports one venv, unless the scan is cancelled first
+func (w *walker) send(venv *model.VenvInfo, results chan<- *model.VenvInfo) error {
+	select {
+	case results <- venv:
+		w.stats.reposFound.Add(1)
+		return nil
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+}
+
+// reportProgress sends the latest counters every progressInterval until stop is closed
+func (w *walker) reportProgress(progress chan<- model.ScanProgress, stop <-chan struct{}) {
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	var last model.ScanProgress
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		case <-w.ctx.Done():
+			return
+		}
+
+		p := w.stats.snapshot()
+		if p == last {
+			continue
+		}
+		select {
+		case progress <- p:
+			last = p
+		case <-stop:
+			return
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
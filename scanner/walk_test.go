@@ -0,0 +1,136 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// makeRepo creates a git repository at path with a venv at each of the
+// given paths relative to it
+func makeRepo(t *testing.T, path string, venvs ...string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(path, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, venv := range venvs {
+		bin := filepath.Join(path, venv, "bin")
+		if err := os.MkdirAll(bin, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, venv, "pyvenv.cfg"), []byte("home = /usr/bin\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(bin, "python"), nil, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// scanPaths runs a scan to the end and returns the venv paths it reported, in order
+func scanPaths(ctx context.Context, root string, opts Options) ([]string, error) {
+	results, progress, errc := ScanForVenvs(ctx, root, opts)
+	go func() {
+		for range progress {
+		}
+	}()
+
+	var paths []string
+	for venv := range results {
+		paths = append(paths, venv.VenvPath)
+	}
+	return paths, <-errc
+}
+
+func TestScanOrder(t *testing.T) {
+	root := t.TempDir()
+	makeRepo(t, filepath.Join(root, "a"), ".venv", "tests/env", "sub/deep/.venv")
+	makeRepo(t, filepath.Join(root, "a-b"), ".venv")
+	makeRepo(t, filepath.Join(root, "a.b"), "venv")
+	makeRepo(t, filepath.Join(root, "B"), ".venv", "z/.venv")
+	makeRepo(t, filepath.Join(root, "group", "one"), ".venv")
+	makeRepo(t, filepath.Join(root, "group", "two"), ".venv", ".tox/py312")
+	makeRepo(t, filepath.Join(root, "group", "two", "nested"), ".venv")
+
+	// The order a sequential walk finds them in
+	var want []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && IsVenv(path) {
+			want = append(want, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want) != 11 {
+		t.Fatalf("test tree has %d venvs, want 11", len(want))
+	}
+
+	for _, workers := range []int{1, 2, 16} {
+		for run := 0; run < 5; run++ {
+			got, err := scanPaths(context.Background(), root, Options{Workers: workers})
+			if err != nil {
+				t.Fatalf("%d workers: scan error = %v", workers, err)
+			}
+			if !slices.Equal(got, want) {
+				t.Fatalf("%d workers: got\n%q\nwant\n%q", workers, got, want)
+			}
+		}
+	}
+}
+
+func TestScanCancel(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i < 50; i++ {
+		makeRepo(t, filepath.Join(root, fmt.Sprintf("repo%02d", i)), ".venv")
+	}
+
+	t.Run("before the scan", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := scanPaths(ctx, root, Options{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("scan error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("after the first result", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		results, progress, errc := ScanForVenvs(ctx, root, Options{Workers: 4})
+		go func() {
+			for range progress {
+			}
+		}()
+
+		// Both channels are closed before the error is sent
+		count := 0
+		for range results {
+			if count == 0 {
+				cancel()
+			}
+			count++
+		}
+		if err := <-errc; !errors.Is(err, context.Canceled) {
+			t.Errorf("scan error = %v, want %v", err, context.Canceled)
+		}
+		if count == 50 {
+			t.Error("scan reported every venv after it was cancelled")
+		}
+	})
+
+	t.Run("missing root", func(t *testing.T) {
+		if _, err := scanPaths(context.Background(), filepath.Join(root, "missing"), Options{}); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("scan error = %v, want %v", err, fs.ErrNotExist)
+		}
+	})
+}
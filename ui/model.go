@@ -1,52 +1,95 @@
 package ui
 
 import (
+	"context"
+	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/raoulg/venvcleaner/cleaner"
 	"github.com/raoulg/venvcleaner/model"
+	"github.com/raoulg/venvcleaner/scanner"
 )
 
 // Model represents the Bubbletea application state
 type Model struct {
 	repos           []model.VenvInfo
-	cursor          int
+	cursor          int             // Index into rows()
+	list            []row           // The rows, rebuilt by refreshRows
+	expanded        map[string]bool // Groups whose members are listed
 	sortMode        model.SortMode
 	state           model.UIState
 	progress        progress.Model
 	spinner         spinner.Model
 	scanResults     <-chan *model.VenvInfo
 	scanProgress    <-chan model.ScanProgress
+	scanErr         <-chan error
+	cancelScan      context.CancelFunc
 	currentScanProg model.ScanProgress
 	progressChan    chan model.Progress
+	cleanErr        chan error
 	totalCleaned    int64
 	cleanedCount    int
-	err             error
+	removed         []model.VenvInfo // Items deleted in the last cleaning run
+	failed          []model.Progress // Items that could not be deleted, with the reason
+	untouched       []model.VenvInfo // Items skipped because the run was cancelled
+	cancelClean     context.CancelFunc
+	cancelling      bool
+	scanFailure     error // Why the scan stopped early; kept for the whole session
+	cleanFailure    error // Why the last cleaning run failed as a whole
 	startPath       string
 	version         string
+	scanOpts        scanner.Options
+	cleanOpts       cleaner.Options
+	showDetails     bool                    // Whether the detail pane of the current row is open
+	details         map[string]*venvDetails // Details read so far, by venv path
 }
 
-// NewModel creates a new UI model
-func NewModel(startPath string, scanResults <-chan *model.VenvInfo, scanProgress <-chan model.ScanProgress, version string) Model {
+// venvDetails are the details of one venv that are too slow to gather
+// during the scan, read in the background when the detail pane shows it
+type venvDetails struct {
+	loading    bool
+	packages   []model.Package
+	err        error // Why the packages could not be read
+	lockfiles  []string
+	lastCommit time.Time // Zero if unknown
+}
+
+// NewModel creates a new UI model and starts scanning startPath in the background.
+// The scan stops when ctx is cancelled or the user quits.
+func NewModel(ctx context.Context, startPath string, scanOpts scanner.Options, version string, cleanOpts cleaner.Options) Model {
+	ctx, cancelScan := context.WithCancel(ctx)
+	scanResults, scanProgress, scanErr := scanner.ScanForVenvs(ctx, startPath, scanOpts)
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 
 	p := progress.New(progress.WithDefaultGradient())
 
+	// Resolve the tool once, so the views can tell whether space is freed
+	cleanOpts.Tool = cleanOpts.RemovalTool()
+
 	return Model{
 		repos:        []model.VenvInfo{},
 		cursor:       0,
+		expanded:     make(map[string]bool),
+		details:      make(map[string]*venvDetails),
 		sortMode:     model.SortByTime,
 		state:        model.StateScanning,
 		progress:     p,
 		spinner:      s,
 		scanResults:  scanResults,
 		scanProgress: scanProgress,
-		progressChan: make(chan model.Progress),
+		scanErr:      scanErr,
+		cancelScan:   cancelScan,
 		startPath:    startPath,
 		version:      version,
+		scanOpts:     scanOpts,
+		cleanOpts:    cleanOpts,
 	}
 }
 
@@ -54,18 +97,18 @@ func NewModel(startPath string, scanResults <-chan *model.VenvInfo, scanProgress
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
-		waitForScanResult(m.scanResults),
+		waitForScanResult(m.scanResults, m.scanErr),
 		waitForScanProgress(m.scanProgress),
 	)
 }
 
 // waitForScanResult waits for the next scan result from the channel
-func waitForScanResult(scanResults <-chan *model.VenvInfo) tea.Cmd {
+func waitForScanResult(scanResults <-chan *model.VenvInfo, scanErr <-chan error) tea.Cmd {
 	return func() tea.Msg {
 		result, ok := <-scanResults
 		if !ok {
 			// Channel closed, scanning is done
-			return scanDoneMsg{}
+			return scanDoneMsg{<-scanErr}
 		}
 		return scanResultMsg{result}
 	}
@@ -84,12 +127,12 @@ func waitForScanProgress(scanProgress <-chan model.ScanProgress) tea.Cmd {
 }
 
 // waitForProgress waits for deletion progress updates
-func waitForProgress(progressChan <-chan model.Progress) tea.Cmd {
+func waitForProgress(progressChan <-chan model.Progress, cleanErr <-chan error) tea.Cmd {
 	return func() tea.Msg {
 		progress, ok := <-progressChan
 		if !ok {
 			// Channel closed, cleaning is done
-			return cleanDoneMsg{}
+			return cleanDoneMsg{<-cleanErr}
 		}
 		return cleanProgressMsg{progress}
 	}
@@ -104,13 +147,63 @@ type scanProgressMsg struct {
 	progress model.ScanProgress
 }
 
-type scanDoneMsg struct{}
+type scanDoneMsg struct {
+	err error
+}
 
 type cleanProgressMsg struct {
 	progress model.Progress
 }
 
-type cleanDoneMsg struct{}
+type cleanDoneMsg struct {
+	err error
+}
+
+type detailsLoadedMsg struct {
+	path    string
+	details *venvDetails
+}
+
+// loadDetails reads the details of the current row in the background,
+// unless they are read already or the row is not a single venv
+func (m *Model) loadDetails() tea.Cmd {
+	r, ok := m.currentRow()
+	if !ok || r.repo < 0 || m.repos[r.repo].VenvPath == "" {
+		return nil
+	}
+	venv := m.repos[r.repo]
+	if _, ok := m.details[venv.VenvPath]; ok {
+		return nil
+	}
+
+	m.details[venv.VenvPath] = &venvDetails{loading: true}
+	return func() tea.Msg {
+		details := &venvDetails{}
+		details.packages, details.err = scanner.Packages(venv.VenvPath)
+		details.lockfiles = scanner.Lockfiles(filepath.Dir(venv.VenvPath), venv.RepoPath)
+		if venv.RepoPath != "" {
+			details.lastCommit, _ = scanner.LastCommitTime(venv.RepoPath)
+		}
+		return detailsLoadedMsg{path: venv.VenvPath, details: details}
+	}
+}
+
+// addScanResult adds a scanned entry to the repos. The artifacts of a
+// repository arrive after its venvs, once it is scanned, and become a column
+// on its first venv outside a .tox or .nox group; only without one do they
+// keep a row of their own.
+func (m *Model) addScanResult(result model.VenvInfo) {
+	if result.Kind == model.KindArtifacts {
+		for i, repo := range m.repos {
+			if repo.RepoPath == result.RepoPath && repo.Group == "" && repo.VenvPath != "" {
+				m.repos[i].Artifacts = result.Artifacts
+				m.repos[i].ArtifactsSize = result.ArtifactsSize
+				return
+			}
+		}
+	}
+	m.repos = append(m.repos, result)
+}
 
 // sortRepos sorts the repos based on the current sort mode
 func (m *Model) sortRepos() {
@@ -125,23 +218,83 @@ func (m *Model) sortRepos() {
 		})
 	case model.SortByName:
 		sort.Slice(m.repos, func(i, j int) bool {
-			return m.repos[i].RepoPath < m.repos[j].RepoPath
+			return m.repos[i].Path() < m.repos[j].Path()
+		})
+	case model.SortByVersion:
+		sort.SliceStable(m.repos, func(i, j int) bool {
+			return scanner.ComparePythonVersions(m.repos[i].PythonVersion, m.repos[j].PythonVersion) < 0
 		})
 	}
 }
 
-// toggleSelection toggles the selection state of the current item
+// selectFailed selects exactly what failed to be deleted in the last cleaning
+// run: the venvs and artifacts left of each failed item. The artifacts that
+// did go are dropped from their repository.
+func (m *Model) selectFailed() {
+	failed := make(map[string]model.VenvInfo)
+	for _, p := range m.failed {
+		failed[p.Item.Path()] = p.Item
+	}
+	for i := range m.repos {
+		item, ok := failed[m.repos[i].Path()]
+		m.repos[i].Selected = item.Selected
+		m.repos[i].ArtifactsSelected = item.ArtifactsSelected
+		if ok {
+			m.repos[i].Artifacts, m.repos[i].ArtifactsSize = item.Artifacts, item.ArtifactsSize
+		}
+	}
+}
+
+// selectPythonVersion adds every venv with the same Python minor version as
+// the current row, such as all 3.8 venvs, to the selection
+func (m *Model) selectPythonVersion() {
+	r, ok := m.currentRow()
+	if !ok {
+		return
+	}
+	version := pythonMinor(r.info.PythonVersion)
+	if version == "" {
+		return
+	}
+	for i := range m.repos {
+		if scanner.MatchPythonVersion(m.repos[i].PythonVersion, version) {
+			m.repos[i].Selected = true
+		}
+	}
+}
+
+// pythonMinor shortens a version such as 3.12.4 to its minor version, 3.12
+func pythonMinor(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// toggleSelection toggles the selection state of the current row
 func (m *Model) toggleSelection() {
-	if m.cursor < len(m.repos) {
-		m.repos[m.cursor].Selected = !m.repos[m.cursor].Selected
+	if r, ok := m.currentRow(); ok {
+		m.toggleRow(r)
+	}
+}
+
+// selectedRepos returns the selected repos in display order
+func (m *Model) selectedRepos() []model.VenvInfo {
+	var selected []model.VenvInfo
+	for _, repo := range m.repos {
+		if repo.HasSelection() {
+			selected = append(selected, repo)
+		}
 	}
+	return selected
 }
 
 // selectedCount returns the number of selected repos
 func (m *Model) selectedCount() int {
 	count := 0
 	for _, repo := range m.repos {
-		if repo.Selected {
+		if repo.HasSelection() {
 			count++
 		}
 	}
@@ -151,10 +304,24 @@ func (m *Model) selectedCount() int {
 // selectedSize returns the total size of selected repos
 func (m *Model) selectedSize() int64 {
 	var size int64
+	for _, repo := range m.repos {
+		size += repo.SelectedSize()
+	}
+	return size
+}
+
+// selectedReclaimable returns how many bytes deleting the selected repos
+// frees. Artifacts share no files with venvs and count in full.
+func (m *Model) selectedReclaimable() int64 {
+	var venvs []model.VenvInfo
+	var artifacts int64
 	for _, repo := range m.repos {
 		if repo.Selected {
-			size += repo.Size
+			venvs = append(venvs, repo)
+		}
+		if repo.ArtifactsSelected {
+			artifacts += repo.ArtifactsSize
 		}
 	}
-	return size
+	return model.ReclaimableSize(venvs) + artifacts
 }
@@ -0,0 +1,225 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/raoulg/venvcleaner/cleaner"
+	"github.com/raoulg/venvcleaner/model"
+)
+
+// RestoreModel is the Bubbletea model for the restore screen, which moves
+// trashed virtual environments back to where they came from
+type RestoreModel struct {
+	items    []cleaner.TrashedItem
+	selected []bool
+	cursor   int
+	loading  bool
+	spinner  spinner.Model
+	results  []restoreResult
+	done     bool
+	err      error
+	version  string
+}
+
+// restoreResult is the outcome of restoring one item
+type restoreResult struct {
+	item cleaner.TrashedItem
+	err  error
+}
+
+// Messages
+type trashLoadedMsg struct {
+	items []cleaner.TrashedItem
+	err   error
+}
+
+type restoreDoneMsg struct {
+	results []restoreResult
+}
+
+// NewRestoreModel creates the restore screen model
+func NewRestoreModel(version string) RestoreModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+
+	return RestoreModel{
+		loading: true,
+		spinner: s,
+		version: version,
+	}
+}
+
+// Init starts loading the trash contents
+func (m RestoreModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, loadTrashed)
+}
+
+// loadTrashed reads the trash directories in the background
+func loadTrashed() tea.Msg {
+	items, err := cleaner.ListTrashedVenvs()
+	return trashLoadedMsg{items: items, err: err}
+}
+
+// restoreItems restores the given items in the background
+func restoreItems(items []cleaner.TrashedItem) tea.Cmd {
+	return func() tea.Msg {
+		var results []restoreResult
+		for _, item := range items {
+			results = append(results, restoreResult{item: item, err: cleaner.RestoreTrashed(item)})
+		}
+		return restoreDoneMsg{results}
+	}
+}
+
+// Update handles key presses and background results
+func (m RestoreModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.KeyMsg:
+		if m.done || m.err != nil || (!m.loading && len(m.items) == 0) {
+			// Any key quits
+			return m, tea.Quit
+		}
+		if m.loading {
+			if msg.String() == "q" || msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case "down", "j":
+			if m.cursor < len(m.items)-1 {
+				m.cursor++
+			}
+
+		case " ":
+			m.selected[m.cursor] = !m.selected[m.cursor]
+
+		case "a":
+			for i := range m.selected {
+				m.selected[i] = true
+			}
+
+		case "d":
+			for i := range m.selected {
+				m.selected[i] = false
+			}
+
+		case "enter":
+			var chosen []cleaner.TrashedItem
+			for i, item := range m.items {
+				if m.selected[i] {
+					chosen = append(chosen, item)
+				}
+			}
+			if len(chosen) > 0 {
+				m.loading = true
+				return m, restoreItems(chosen)
+			}
+		}
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case trashLoadedMsg:
+		m.loading = false
+		m.items = msg.items
+		m.selected = make([]bool, len(msg.items))
+		m.err = msg.err
+
+	case restoreDoneMsg:
+		m.loading = false
+		m.done = true
+		m.results = msg.results
+	}
+
+	return m, nil
+}
+
+// View renders the restore screen
+func (m RestoreModel) View() string {
+	var s strings.Builder
+
+	s.WriteString(titleStyle.Render(fmt.Sprintf("♻️  VenvCleaner v%s - Restore", m.version)))
+	s.WriteString("\n")
+	s.WriteString(accentPink.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.err != nil:
+		s.WriteString(warningStyle.Render(fmt.Sprintf("Error reading trash: %v", m.err)))
+		s.WriteString("\n\n")
+		s.WriteString(helpStyle.Render("💡 Press any key to exit"))
+
+	case m.loading:
+		s.WriteString(headerStyle.Render(fmt.Sprintf("%s Working...", m.spinner.View())))
+
+	case m.done:
+		for _, result := range m.results {
+			if result.err != nil {
+				s.WriteString(warningStyle.Render("✗ ") + subheaderStyle.Render(result.err.Error()))
+			} else {
+				s.WriteString(successStyle.Render("✓ ") + pathStyle.Render(result.item.OriginalPath))
+			}
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render("💡 Press any key to exit"))
+
+	case len(m.items) == 0:
+		s.WriteString(subheaderStyle.Render("No virtual environments found in the trash."))
+		s.WriteString("\n\n")
+		s.WriteString(helpStyle.Render("💡 Press any key to exit"))
+
+	default:
+		s.WriteString(accentCyan.Render("🗂️  ") + headerStyle.Render("Select virtual environments to restore:"))
+		s.WriteString("\n")
+
+		separator := separatorStyle.Render(" │ ")
+		for i, item := range m.items {
+			checkbox := "[ ]"
+			if m.selected[i] {
+				checkbox = "[✓]"
+			}
+			cursor := "  "
+			if i == m.cursor {
+				cursor = "→ "
+			}
+
+			prefix := cursor + checkbox + " " + item.DeletedAt.Format("2006-01-02 15:04")
+			if m.selected[i] {
+				prefix = selectedStyle.Render(prefix)
+			} else if i == m.cursor {
+				prefix = cursorStyle.Render(prefix)
+			}
+
+			size := model.FormatSize(item.Size)
+			s.WriteString(prefix + separator +
+				sizeSmallStyle.Render(size+strings.Repeat(" ", max(0, 9-len(size)))) + separator +
+				pathStyle.Render(item.OriginalPath))
+			if item.Kind != model.KindVenv {
+				s.WriteString(separator + accentCyan.Render(string(item.Kind)))
+			}
+			s.WriteString("\n")
+		}
+
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render("💡 ↑/↓: navigate | ⎵: toggle | ↵: restore | a/d: all/none | q: quit"))
+	}
+
+	return s.String()
+}
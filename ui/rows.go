@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/raoulg/venvcleaner/model"
+)
+
+// row is one line of the selection list: a venv, or the header of a group of
+// venvs such as the environments in one .tox directory. Rows carry what they
+// show, so rendering does not walk the repos again for every line.
+type row struct {
+	repo    int            // Index into Model.repos, -1 for a group header
+	group   string         // Group the row heads or belongs to, "" if ungrouped
+	members []int          // For a group header, indexes of its members in display order
+	info    model.VenvInfo // The venv shown; a summary for a group header
+	path    string         // The path shown, see rowPath
+	partial bool           // Whether some, but not all, members of the group are selected
+}
+
+// rows returns the list lines in display order, as built by refreshRows
+func (m *Model) rows() []row {
+	return m.list
+}
+
+// refreshRows rebuilds the list lines; call it whenever the repos, their
+// order, their selection or the expanded groups change. The members of a
+// group are listed under a header at the position of the group's first
+// member, and only while the group is expanded.
+func (m *Model) refreshRows() {
+	members := make(map[string][]int)
+	for i, repo := range m.repos {
+		if repo.Group != "" {
+			members[repo.Group] = append(members[repo.Group], i)
+		}
+	}
+
+	var list []row
+	seen := make(map[string]bool)
+	for i, repo := range m.repos {
+		if repo.Group == "" {
+			list = append(list, m.newRow(row{repo: i}))
+			continue
+		}
+		if seen[repo.Group] {
+			continue
+		}
+		seen[repo.Group] = true
+
+		list = append(list, m.newRow(row{repo: -1, group: repo.Group, members: members[repo.Group]}))
+		if m.expanded[repo.Group] {
+			for _, member := range members[repo.Group] {
+				list = append(list, m.newRow(row{repo: member, group: repo.Group}))
+			}
+		}
+	}
+	m.list = list
+}
+
+// newRow fills in what a row shows
+func (m *Model) newRow(r row) row {
+	if r.repo >= 0 {
+		r.info = m.repos[r.repo]
+	} else {
+		r.info = m.summarize(r.group, r.members)
+		for _, i := range r.members {
+			if m.repos[i].Selected {
+				r.partial = !r.info.Selected
+				break
+			}
+		}
+	}
+	r.path = m.rowPath(r)
+	return r
+}
+
+// currentRow returns the row under the cursor
+func (m *Model) currentRow() (row, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.list) {
+		return row{}, false
+	}
+	return m.list[m.cursor], true
+}
+
+// summarize returns a group as a single venv: its totals, its newest
+// modification, selected only if all members are, and its members as the
+// largest directories
+func (m *Model) summarize(group string, indexes []int) model.VenvInfo {
+	var members []model.VenvInfo
+	for _, i := range indexes {
+		members = append(members, m.repos[i])
+	}
+
+	info := model.VenvInfo{
+		Kind:          members[0].Kind,
+		RepoPath:      members[0].RepoPath,
+		Branch:        members[0].Branch,
+		Worktree:      members[0].Worktree,
+		PythonVersion: members[0].PythonVersion,
+		VenvPath:      group,
+		Reclaimable:   model.ReclaimableSize(members),
+		Selected:      true,
+		Recreatable:   true,
+	}
+	for _, member := range members {
+		if member.LastModified.After(info.LastModified) {
+			info.LastModified = member.LastModified
+		}
+		info.Size += member.Size
+		info.DiskUsage += member.DiskUsage
+		info.FileCount += member.FileCount
+		info.DirCount += member.DirCount
+		info.Selected = info.Selected && member.Selected
+		info.Recreatable = info.Recreatable && member.Recreatable
+		if member.Broken && !info.Broken {
+			info.Broken, info.BrokenReason = true, member.BrokenReason
+		}
+		if member.PythonVersion != info.PythonVersion {
+			info.PythonVersion = "" // Mixed versions
+		}
+		info.LargestDirs = append(info.LargestDirs, model.DirSize{Path: member.VenvPath, Size: member.Size})
+	}
+	sort.Slice(info.LargestDirs, func(i, j int) bool {
+		return info.LargestDirs[i].Size > info.LargestDirs[j].Size
+	})
+
+	return info
+}
+
+// rowPath returns the path shown on a row, relative to the start path when
+// below it. Group headers show whether they are expanded and how many
+// environments they hold, members are indented below them.
+func (m *Model) rowPath(r row) string {
+	path := r.info.Path()
+	if home, err := filepath.Abs(m.startPath); err == nil {
+		if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = "./" + rel
+		}
+	}
+
+	switch {
+	case r.repo < 0:
+		marker := "+"
+		if m.expanded[r.group] {
+			marker = "-"
+		}
+		count := len(r.members)
+		envs := "envs"
+		if count == 1 {
+			envs = "env"
+		}
+		return fmt.Sprintf("%s %s (%d %s)", marker, path, count, envs)
+	case r.group != "":
+		return "    " + path
+	default:
+		return path
+	}
+}
+
+// toggleRow toggles the selection of a row; on a group header it selects all
+// members unless they are all selected already. A row without a venv holds
+// only artifacts, and toggles those.
+func (m *Model) toggleRow(r row) {
+	if r.repo >= 0 && m.repos[r.repo].VenvPath == "" {
+		m.toggleArtifacts(r)
+		return
+	}
+	if r.repo >= 0 {
+		m.repos[r.repo].Selected = !m.repos[r.repo].Selected
+		return
+	}
+
+	selected := !r.info.Selected
+	for _, i := range r.members {
+		m.repos[i].Selected = selected
+	}
+}
+
+// toggleArtifacts toggles the selection of the artifacts of the row's
+// repository, shown on its venv row or on a row of their own
+func (m *Model) toggleArtifacts(r row) {
+	repoPath := r.info.RepoPath
+	if repoPath == "" {
+		return
+	}
+
+	var owners []int
+	selected := false
+	for i, repo := range m.repos {
+		if repo.RepoPath == repoPath && len(repo.Artifacts) > 0 {
+			owners = append(owners, i)
+			selected = selected || !repo.ArtifactsSelected
+		}
+	}
+	for _, i := range owners {
+		m.repos[i].ArtifactsSelected = selected
+	}
+}
+
+// setExpanded expands or collapses the group of the current row. Collapsing
+// from a member moves the cursor to the group's header.
+func (m *Model) setExpanded(expanded bool) {
+	r, ok := m.currentRow()
+	if !ok || r.group == "" {
+		return
+	}
+	m.expanded[r.group] = expanded
+	m.refreshRows()
+
+	if !expanded {
+		for i, other := range m.list {
+			if other.repo < 0 && other.group == r.group {
+				m.cursor = i
+				break
+			}
+		}
+	}
+}
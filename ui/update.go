@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"context"
+	"errors"
+
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/raoulg/venvcleaner/cleaner"
@@ -14,8 +17,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch m.state {
 		case model.StateScanning:
-			// Allow quitting during scanning
+			// Allow quitting during scanning, which also stops the walk
 			if msg.String() == "q" || msg.String() == "ctrl+c" {
+				m.cancelScan()
 				return m, tea.Quit
 			}
 
@@ -30,12 +34,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 			case "down", "j":
-				if m.cursor < len(m.repos)-1 {
+				if m.cursor < len(m.rows())-1 {
 					m.cursor++
 				}
 
+			case "right", "l":
+				m.setExpanded(true)
+
+			case "left", "h":
+				m.setExpanded(false)
+
 			case " ":
 				m.toggleSelection()
+				m.refreshRows()
 
 			case "enter":
 				// Only proceed if something is selected
@@ -46,48 +57,97 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "t":
 				m.sortMode = model.SortByTime
 				m.sortRepos()
+				m.refreshRows()
 				m.cursor = 0
 
 			case "s":
 				m.sortMode = model.SortBySize
 				m.sortRepos()
+				m.refreshRows()
 				m.cursor = 0
 
 			case "n":
 				m.sortMode = model.SortByName
 				m.sortRepos()
+				m.refreshRows()
+				m.cursor = 0
+
+			case "v":
+				m.sortMode = model.SortByVersion
+				m.sortRepos()
+				m.refreshRows()
 				m.cursor = 0
 
+			case "b":
+				// Add all broken venvs to the selection
+				for i := range m.repos {
+					if m.repos[i].Broken {
+						m.repos[i].Selected = true
+					}
+				}
+				m.refreshRows()
+
+			case "p":
+				// Add all venvs of the current row's Python version to the selection
+				m.selectPythonVersion()
+				m.refreshRows()
+
 			case "a":
-				// Select all
+				// Select all venvs
 				for i := range m.repos {
-					m.repos[i].Selected = true
+					m.repos[i].Selected = m.repos[i].VenvPath != ""
 				}
+				m.refreshRows()
 
 			case "d":
-				// Deselect all
+				// Deselect all, artifacts included
 				for i := range m.repos {
 					m.repos[i].Selected = false
+					m.repos[i].ArtifactsSelected = false
+				}
+				m.refreshRows()
+
+			case "x":
+				// Toggle the artifacts of the current row
+				if r, ok := m.currentRow(); ok {
+					m.toggleArtifacts(r)
+					m.refreshRows()
 				}
+
+			case "i":
+				// Show or hide the detail pane
+				m.showDetails = !m.showDetails
+			}
+
+			// Keep the detail pane in step with the cursor
+			if m.showDetails {
+				return m, m.loadDetails()
 			}
 
 		case model.StateConfirming:
 			switch msg.String() {
 			case "y", "Y", "enter":
 				// Start deletion
-				m.state = model.StateCleaning
-				return m, tea.Batch(
-					startCleaning(m.repos, m.progressChan),
-					waitForProgress(m.progressChan),
-				)
+				return m.startCleaning()
 
 			case "n", "N", "q", "ctrl+c":
 				// Go back to selection
 				m.state = model.StateSelecting
 			}
 
+		case model.StateCleaning:
+			// Stop handing out new deletions; the ones in progress finish
+			if msg.String() == "ctrl+c" || msg.String() == "esc" {
+				m.cancelling = true
+				m.cancelClean()
+			}
+
 		case model.StateDone:
-			// Any key quits
+			// Retry only the failed items, any other key quits
+			if msg.String() == "r" && len(m.failed) > 0 {
+				m.selectFailed()
+				return m.startCleaning()
+			}
 			return m, tea.Quit
 		}
 
@@ -98,10 +158,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case scanResultMsg:
 		// Add new repo to list
-		m.repos = append(m.repos, *msg.result)
+		m.addScanResult(*msg.result)
 		m.sortRepos()
 		// Wait for next result
-		return m, waitForScanResult(m.scanResults)
+		return m, waitForScanResult(m.scanResults, m.scanErr)
 
 	case scanProgressMsg:
 		// Update current scan progress
@@ -111,6 +171,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case scanDoneMsg:
 		// Scanning complete
+		m.cancelScan()
+		m.scanFailure = msg.err
+		m.refreshRows()
 		if len(m.repos) == 0 {
 			// No repos found, go to done state with message
 			m.state = model.StateDone
@@ -121,20 +184,58 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case cleanProgressMsg:
 		m.cleanedCount = msg.progress.Current
 		m.totalCleaned = msg.progress.Size
+		if errors.Is(msg.progress.Err, context.Canceled) {
+			m.untouched = append(m.untouched, msg.progress.Item)
+		} else if msg.progress.Err != nil {
+			m.failed = append(m.failed, msg.progress)
+		} else {
+			m.removed = append(m.removed, msg.progress.Item)
+		}
 		// Wait for next progress update
-		return m, waitForProgress(m.progressChan)
+		return m, waitForProgress(m.progressChan, m.cleanErr)
+
+	case detailsLoadedMsg:
+		m.details[msg.path] = msg.details
 
 	case cleanDoneMsg:
+		m.cancelClean()
+		m.cleanFailure = msg.err
 		m.state = model.StateDone
 	}
 
 	return m, nil
 }
 
-// startCleaning begins the deletion process in a goroutine
-func startCleaning(repos []model.VenvInfo, progressChan chan model.Progress) tea.Cmd {
+// startCleaning resets the run results and deletes the selected repos
+func (m Model) startCleaning() (tea.Model, tea.Cmd) {
+	m.state = model.StateCleaning
+	m.cleanedCount = 0
+	m.totalCleaned = 0
+	m.removed = nil
+	m.failed = nil
+	m.untouched = nil
+	m.cancelling = false
+	m.cleanFailure = nil
+
+	// DeleteSelected closes the progress channel, so every run needs fresh channels
+	m.progressChan = make(chan model.Progress)
+	m.cleanErr = make(chan error, 1)
+
+	var ctx context.Context
+	ctx, m.cancelClean = context.WithCancel(context.Background())
+
+	return m, tea.Batch(
+		runCleaner(ctx, m.repos, m.progressChan, m.cleanErr, m.cleanOpts),
+		waitForProgress(m.progressChan, m.cleanErr),
+	)
+}
+
+// runCleaner begins the deletion process in a goroutine
+func runCleaner(ctx context.Context, repos []model.VenvInfo, progressChan chan model.Progress, cleanErr chan error, opts cleaner.Options) tea.Cmd {
 	return func() tea.Msg {
-		go cleaner.DeleteSelected(repos, progressChan)
+		go func() {
+			cleanErr <- cleaner.DeleteSelected(ctx, repos, progressChan, opts)
+		}()
 		return nil
 	}
 }
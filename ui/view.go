@@ -1,12 +1,14 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/raoulg/venvcleaner/cleaner"
 	"github.com/raoulg/venvcleaner/model"
 )
 
@@ -171,7 +173,7 @@ func (m Model) renderScanning() string {
 	s.WriteString(accentCyan.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
 	s.WriteString("\n\n")
 
-	s.WriteString(headerStyle.Render(fmt.Sprintf("%s Scanning for .venv folders...", m.spinner.View())))
+	s.WriteString(headerStyle.Render(fmt.Sprintf("%s Scanning for virtual environments...", m.spinner.View())))
 	s.WriteString("\n\n")
 
 	// Show current path being scanned with box
@@ -200,7 +202,7 @@ func (m Model) renderScanning() string {
 		counterStyle.Render(fmt.Sprintf("%d", m.currentScanProg.FoldersScanned)))
 	s.WriteString("\n")
 	s.WriteString(accentCyan.Render("✅ ") +
-		headerStyle.Render("Repos with .venv: ") +
+		headerStyle.Render("Venvs found: ") +
 		successStyle.Render(fmt.Sprintf("%d", m.currentScanProg.ReposFound)))
 
 	s.WriteString("\n\n")
@@ -211,10 +213,19 @@ func (m Model) renderScanning() string {
 	return s.String()
 }
 
+// searchedIn tells where the scan looked for venvs, for when it found none:
+// only in git repositories, unless --include-non-repo was given
+func (m Model) searchedIn() string {
+	if m.scanOpts.IncludeNonRepo {
+		return ""
+	}
+	return " in git repositories"
+}
+
 func (m Model) renderSelecting() string {
 	if len(m.repos) == 0 {
 		return titleStyle.Render(fmt.Sprintf("🔍 VenvCleaner v%s", m.version)) + "\n\n" +
-			subheaderStyle.Render("No repositories with .venv folders found.") + "\n\n" +
+			subheaderStyle.Render("No virtual environments found"+m.searchedIn()+".") + "\n\n" +
 			helpStyle.Render("💡 Press any key to exit")
 	}
 
@@ -225,7 +236,7 @@ func (m Model) renderSelecting() string {
 	s.WriteString("\n")
 	s.WriteString(accentPink.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
 	s.WriteString("\n\n")
-	s.WriteString(accentCyan.Render("🗂️  ") + headerStyle.Render("Select .venv folders to remove:"))
+	s.WriteString(accentCyan.Render("🗂️  ") + headerStyle.Render("Select virtual environments to remove:"))
 	s.WriteString("\n")
 
 	// Sort mode indicator
@@ -237,33 +248,53 @@ func (m Model) renderSelecting() string {
 		sortModeStr = "Sorted by: Size (largest first)"
 	case model.SortByName:
 		sortModeStr = "Sorted by: Name (A-Z)"
+	case model.SortByVersion:
+		sortModeStr = "Sorted by: Python version (oldest first)"
 	}
 	s.WriteString(headerStyle.Render(sortModeStr))
 	s.WriteString("\n\n")
 
+	// The scan ended early, so the list may be incomplete
+	if m.scanFailure != nil {
+		s.WriteString(warningStyle.Render("⚠️  ") + subheaderStyle.Render(fmt.Sprintf("Scan incomplete: %v", m.scanFailure)))
+		s.WriteString("\n\n")
+	}
+
 	// Calculate column widths for alignment
-	pathWidth, dateWidth := m.calculateColumnWidths()
+	pathWidth, dateWidth, versionWidth := m.calculateColumnWidths()
 
 	// Render list of repos (with scrolling if needed)
-	start, end := m.getVisibleRange()
+	rows := m.rows()
+	start, end := m.getVisibleRange(len(rows))
 	for i := start; i < end; i++ {
-		s.WriteString(m.renderRepoLine(i, pathWidth, dateWidth))
+		s.WriteString(m.renderRepoLine(rows[i], i, pathWidth, dateWidth, versionWidth))
 		s.WriteString("\n")
 	}
 
+	// Details of the venv or group under the cursor
+	if r, ok := m.currentRow(); ok {
+		s.WriteString("\n")
+		if m.showDetails {
+			s.WriteString(m.renderDetails(r))
+		} else {
+			s.WriteString(renderVenvStats(r.info))
+		}
+	}
+
 	// Footer with controls and summary
 	s.WriteString("\n")
 	s.WriteString(accentYellow.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
 	s.WriteString("\n")
 	s.WriteString(accentPink.Render("📊 ") + footerStyle.Render(fmt.Sprintf(
-		"Selected: %s/%s | Total size: %s",
+		"Selected: %s/%s | Total size: %s | Reclaimable: %s",
 		counterStyle.Render(fmt.Sprintf("%d", m.selectedCount())),
 		counterStyle.Render(fmt.Sprintf("%d", len(m.repos))),
-		successStyle.Render(formatSize(m.selectedSize())),
+		successStyle.Render(model.FormatSize(m.selectedSize())),
+		successStyle.Render(model.FormatSize(m.selectedReclaimable())),
 	)))
 	s.WriteString("\n")
 	s.WriteString(helpStyle.Render(
-		"💡 ↑/↓: navigate | ⎵: toggle | ↵: confirm | t/s/n: sort | a/d: all/none | q: quit",
+		"💡 ↑/↓: navigate | →/←: expand/collapse | ⎵: toggle | x: toggle artifacts | ↵: confirm | t/s/n/v: sort | a/d: all/none | b: broken | p: same Python | i: details | q: quit",
 	))
 
 	return s.String()
@@ -272,33 +303,27 @@ func (m Model) renderSelecting() string {
 func (m Model) renderConfirming() string {
 	var s strings.Builder
 
-	s.WriteString(warningStyle.Render("⚠️  Confirm Deletion"))
+	if m.cleanOpts.DryRun {
+		s.WriteString(warningStyle.Render("🧪 Confirm Dry Run"))
+		s.WriteString("\n\n")
+		s.WriteString(accentYellow.Render("DRY RUN: ") + subheaderStyle.Render("nothing will be deleted, the run is only simulated."))
+		s.WriteString("\n\n")
+		s.WriteString(headerStyle.Render("The following virtual environments would be deleted:"))
+	} else {
+		s.WriteString(warningStyle.Render("⚠️  Confirm Deletion"))
+		s.WriteString("\n\n")
+		s.WriteString(headerStyle.Render("You are about to delete the following virtual environments:"))
+	}
 	s.WriteString("\n\n")
 
-	s.WriteString(headerStyle.Render("You are about to delete the following .venv folders:"))
-	s.WriteString("\n\n")
-
-	for _, repo := range m.repos {
-		if repo.Selected {
-			sizeColored := formatSize(repo.Size)
-			if repo.Size >= 1024*1024*1024 {
-				sizeColored = sizeHugeStyle.Render(sizeColored)
-			} else if repo.Size >= 500*1024*1024 {
-				sizeColored = sizeLargeStyle.Render(sizeColored)
-			} else if repo.Size >= 50*1024*1024 {
-				sizeColored = sizeMediumStyle.Render(sizeColored)
-			} else {
-				sizeColored = sizeSmallStyle.Render(sizeColored)
-			}
-			s.WriteString(fmt.Sprintf("  • %s (%s)\n", pathStyle.Render(repo.RepoPath), sizeColored))
-		}
-	}
+	s.WriteString(renderVenvList(m.selectedRepos()))
 
 	s.WriteString("\n")
 	s.WriteString(footerStyle.Render(fmt.Sprintf(
-		"Total: %s folders | %s",
+		"Total: %s folders | %s | %s reclaimable",
 		counterStyle.Render(fmt.Sprintf("%d", m.selectedCount())),
-		warningStyle.Render(formatSize(m.selectedSize())),
+		warningStyle.Render(model.FormatSize(m.selectedSize())),
+		warningStyle.Render(model.FormatSize(m.selectedReclaimable())),
 	)))
 	s.WriteString("\n\n")
 	s.WriteString(helpStyle.Render("Are you sure? (y/N): "))
@@ -306,10 +331,223 @@ func (m Model) renderConfirming() string {
 	return s.String()
 }
 
+// renderVenvList renders one bullet per repo with the colored size of what
+// is selected of it
+func renderVenvList(repos []model.VenvInfo) string {
+	var s strings.Builder
+
+	for _, repo := range repos {
+		size := repo.SelectedSize()
+		sizeColored := model.FormatSize(size)
+		if size >= 1024*1024*1024 {
+			sizeColored = sizeHugeStyle.Render(sizeColored)
+		} else if size >= 500*1024*1024 {
+			sizeColored = sizeLargeStyle.Render(sizeColored)
+		} else if size >= 50*1024*1024 {
+			sizeColored = sizeMediumStyle.Render(sizeColored)
+		} else {
+			sizeColored = sizeSmallStyle.Render(sizeColored)
+		}
+
+		details := []string{sizeColored}
+		if repo.Selected {
+			details = append(details, formatCount(repo.FileCount)+" files")
+		}
+		if repo.ArtifactsSelected {
+			details = append(details, fmt.Sprintf("%d artifact folders", len(repo.Artifacts)))
+		}
+		s.WriteString(fmt.Sprintf("  • %s (%s)\n",
+			pathStyle.Render(repo.Path()), strings.Join(details, ", ")))
+	}
+
+	return s.String()
+}
+
+// renderVenvStats renders the file counts and largest folders of one venv
+func renderVenvStats(venv model.VenvInfo) string {
+	var s strings.Builder
+
+	if venv.VenvPath != "" {
+		s.WriteString(accentCyan.Render("📦 ") + subheaderStyle.Render(fmt.Sprintf(
+			"%s files in %s folders", formatCount(venv.FileCount), formatCount(venv.DirCount))))
+		s.WriteString("\n")
+	}
+
+	// Centrally stored venvs live away from their project
+	if venv.Kind == model.KindPoetry || venv.Kind == model.KindPipenv {
+		project := venv.RepoPath
+		switch {
+		case project == "":
+			project = "unknown"
+		case venv.ProjectMissing:
+			project += " (no longer exists)"
+		}
+		s.WriteString(accentCyan.Render("🔗 ") + subheaderStyle.Render(fmt.Sprintf("%s env of project %s", venv.Kind, project)))
+		s.WriteString("\n")
+	}
+
+	if venv.PythonVersion != "" || venv.Creator != "" {
+		pythonLine := "Python " + venv.PythonVersion
+		if venv.Creator != "" {
+			pythonLine += " (" + venv.Creator + ")"
+		}
+		if venv.BaseInterpreter != "" {
+			pythonLine += " from " + venv.BaseInterpreter
+		}
+		if venv.SystemSitePackages {
+			pythonLine += ", with system site-packages"
+		}
+		s.WriteString(accentCyan.Render("🐍 ") + subheaderStyle.Render(pythonLine))
+		s.WriteString("\n")
+	}
+
+	if venv.Broken {
+		s.WriteString(warningStyle.Render("💔 ") + subheaderStyle.Render("Broken: "+venv.BrokenReason))
+		s.WriteString("\n")
+	}
+
+	if venv.Branch != "" || venv.Worktree {
+		repoLine := "Branch: " + venv.Branch
+		if venv.Worktree {
+			repoLine = fmt.Sprintf("Worktree of %s on %s", venv.MainRepoPath, venv.Branch)
+		}
+		s.WriteString(accentPurple.Render("🌿 ") + subheaderStyle.Render(repoLine))
+		s.WriteString("\n")
+	}
+
+	if len(venv.Artifacts) > 0 {
+		s.WriteString(accentYellow.Render("🧹 ") + subheaderStyle.Render(fmt.Sprintf(
+			"%d cache and build folders in the repository, %s", len(venv.Artifacts), model.FormatSize(venv.ArtifactsSize))))
+		s.WriteString("\n")
+	}
+
+	if len(venv.LargestDirs) > 0 {
+		var largest []string
+		for _, dir := range venv.LargestDirs {
+			largest = append(largest, fmt.Sprintf("%s %s",
+				pathStyle.Render(filepath.Base(dir.Path)), sizeSmallStyle.Render(model.FormatSize(dir.Size))))
+		}
+		s.WriteString(accentPink.Render("🏋️  ") + subheaderStyle.Render("Largest: ") +
+			strings.Join(largest, separatorStyle.Render(" │ ")))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+// packagesShown is how many of the largest packages the detail pane lists
+const packagesShown = 5
+
+// renderDetails renders the detail pane of a row: full paths, Python, counts,
+// lockfiles, git state and the largest installed packages. Group headers and
+// rows without a venv only have the summary of renderVenvStats.
+func (m Model) renderDetails(r row) string {
+	if r.repo < 0 || m.repos[r.repo].VenvPath == "" {
+		stats := renderVenvStats(r.info)
+		if r.repo < 0 {
+			stats += subheaderStyle.Render("Expand the group to see the details of each environment") + "\n"
+		}
+		return stats
+	}
+
+	venv := m.repos[r.repo]
+	details := m.details[venv.VenvPath]
+
+	var s strings.Builder
+	line := func(icon, label, value string) {
+		s.WriteString(icon + " " + subheaderStyle.Render(fmt.Sprintf("%-11s", label)) + value + "\n")
+	}
+
+	line(accentCyan.Render("📁"), "Path:", pathStyle.Render(venv.VenvPath))
+
+	repoLine := "none"
+	if venv.RepoPath != "" {
+		repoLine = pathStyle.Render(venv.RepoPath)
+		if venv.Branch != "" {
+			repoLine += subheaderStyle.Render(" on ") + accentPurple.Render(venv.Branch)
+		}
+		if venv.Worktree {
+			repoLine += subheaderStyle.Render(", worktree of " + venv.MainRepoPath)
+		}
+		if details != nil && !details.lastCommit.IsZero() {
+			repoLine += subheaderStyle.Render(fmt.Sprintf(", last commit %s (%s)",
+				details.lastCommit.Format("2006-01-02"), formatDate(details.lastCommit)))
+		}
+	}
+	line(accentPurple.Render("🌿"), "Repo:", repoLine)
+
+	if venv.PythonVersion != "" || venv.Creator != "" {
+		python := accentPurple.Render(venv.PythonVersion)
+		if venv.Creator != "" {
+			python += subheaderStyle.Render(" (" + venv.Creator + ")")
+		}
+		if venv.BaseInterpreter != "" {
+			python += subheaderStyle.Render(" from " + venv.BaseInterpreter)
+		}
+		line(accentCyan.Render("🐍"), "Python:", python)
+	}
+	if venv.Broken {
+		line(warningStyle.Render("💔"), "Broken:", warningStyle.Render(venv.BrokenReason))
+	}
+
+	line(accentCyan.Render("📦"), "Files:", subheaderStyle.Render(fmt.Sprintf("%s files in %s folders, %s",
+		formatCount(venv.FileCount), formatCount(venv.DirCount), model.FormatSize(venv.Size))))
+
+	if details == nil || details.loading {
+		s.WriteString(m.spinner.View() + " " + subheaderStyle.Render("Reading packages and lockfiles..."))
+		s.WriteString("\n")
+		return s.String()
+	}
+
+	lockfiles := accentYellow.Render("none")
+	if len(details.lockfiles) > 0 {
+		lockfiles = successStyle.Render(strings.Join(details.lockfiles, ", "))
+	}
+	line(accentYellow.Render("🔒"), "Lockfiles:", lockfiles)
+
+	switch {
+	case details.err != nil:
+		line(warningStyle.Render("❌"), "Packages:", subheaderStyle.Render(fmt.Sprintf("cannot read: %v", details.err)))
+		return s.String()
+	case len(details.packages) == 0:
+		line(accentPink.Render("📚"), "Packages:", subheaderStyle.Render("none found"))
+		return s.String()
+	}
+
+	var total int64
+	nameWidth, versionWidth := 0, 0
+	for i, pkg := range details.packages {
+		total += pkg.Size
+		if i < packagesShown {
+			nameWidth = max(nameWidth, len(pkg.Name))
+			versionWidth = max(versionWidth, len(pkg.Version))
+		}
+	}
+	line(accentPink.Render("📚"), "Packages:", subheaderStyle.Render(fmt.Sprintf(
+		"%d, %s; largest:", len(details.packages), model.FormatSize(total))))
+	for i, pkg := range details.packages {
+		if i == packagesShown {
+			break
+		}
+		s.WriteString(fmt.Sprintf("   %s%s %s%s %s\n",
+			pathStyle.Render(pkg.Name), strings.Repeat(" ", nameWidth-len(pkg.Name)),
+			accentPurple.Render(pkg.Version), strings.Repeat(" ", versionWidth-len(pkg.Version)),
+			sizeSmallStyle.Render(model.FormatSize(pkg.Size))))
+	}
+
+	return s.String()
+}
+
 func (m Model) renderCleaning() string {
 	var s strings.Builder
 
-	s.WriteString(headerStyle.Render("🧹 Cleaning..."))
+	if m.cancelling {
+		s.WriteString(warningStyle.Render("🛑 Cancelling, finishing the folders in progress..."))
+	} else if m.cleanOpts.DryRun {
+		s.WriteString(headerStyle.Render("🧪 Simulating cleaning (dry run)..."))
+	} else {
+		s.WriteString(headerStyle.Render("🧹 Cleaning..."))
+	}
 	s.WriteString("\n\n")
 
 	total := m.selectedCount()
@@ -318,68 +556,175 @@ func (m Model) renderCleaning() string {
 		s.WriteString(m.progress.ViewAs(percent))
 		s.WriteString("\n\n")
 		s.WriteString(fmt.Sprintf(
-			"%s %s/%s folders removed\n",
+			"%s %s/%s folders processed\n",
 			successStyle.Render("Progress:"),
 			counterStyle.Render(fmt.Sprintf("%d", m.cleanedCount)),
 			counterStyle.Render(fmt.Sprintf("%d", total)),
 		))
 		s.WriteString(fmt.Sprintf("%s %s\n",
-			successStyle.Render("Freed:"),
-			footerStyle.Render(formatSize(m.totalCleaned))))
+			successStyle.Render("Space "+m.freedLabel()+":"),
+			footerStyle.Render(model.FormatSize(m.totalCleaned))))
+	}
+
+	if !m.cancelling {
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render("💡 esc/ctrl+c: cancel"))
 	}
 
 	return s.String()
 }
 
+// freedLabel describes what happens to the space of removed folders: "freed",
+// or "moved to the trash" when the tool keeps them
+func (m Model) freedLabel() string {
+	if place := cleaner.RecoveryPlace(m.cleanOpts.Tool); place != "" && !m.cleanOpts.DryRun {
+		return "moved to " + place
+	}
+	return "freed"
+}
+
 func (m Model) renderDone() string {
 	var s strings.Builder
 
 	// Big celebration header
-	s.WriteString(successStyle.Render("✨ ✅ Done! ✅ ✨"))
+	if len(m.untouched) > 0 {
+		s.WriteString(warningStyle.Render("🛑 Cleaning cancelled"))
+	} else if m.cleanOpts.DryRun {
+		s.WriteString(successStyle.Render("✨ 🧪 Dry run complete 🧪 ✨"))
+	} else if len(m.failed) > 0 {
+		s.WriteString(warningStyle.Render("⚠️  Done, with errors"))
+	} else {
+		s.WriteString(successStyle.Render("✨ ✅ Done! ✅ ✨"))
+	}
 	s.WriteString("\n")
 	s.WriteString(accentCyan.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
 	s.WriteString("\n\n")
 
-	if len(m.repos) == 0 {
-		s.WriteString(accentYellow.Render("ℹ️  ") + subheaderStyle.Render("No repositories with .venv folders were found."))
+	if len(m.repos) == 0 && m.scanFailure != nil {
+		s.WriteString(warningStyle.Render("⚠️  ") + subheaderStyle.Render(fmt.Sprintf("Scan failed: %v", m.scanFailure)))
+		s.WriteString("\n")
+	} else if len(m.repos) == 0 {
+		s.WriteString(accentYellow.Render("ℹ️  ") + subheaderStyle.Render("No virtual environments were found"+m.searchedIn()+"."))
 		s.WriteString("\n")
-	} else if m.cleanedCount > 0 {
+	} else if m.cleanOpts.DryRun && len(m.removed) > 0 && len(m.untouched) == 0 {
+		// Simulated run: say so loudly and list exactly what would have gone
+		s.WriteString(accentYellow.Render("🧪 DRY RUN: ") + subheaderStyle.Render("nothing was deleted."))
+		s.WriteString("\n\n")
+		s.WriteString(accentPink.Render("🎯 ") + headerStyle.Render(fmt.Sprintf(
+			"Would have removed %s virtual environments:",
+			successStyle.Render(fmt.Sprintf("%d", len(m.removed))),
+		)))
+		s.WriteString("\n\n")
+		s.WriteString(renderVenvList(m.removed))
+		s.WriteString("\n")
+		s.WriteString(accentYellow.Render("💾 ") + footerStyle.Render("Space that would be freed: "))
+		s.WriteString(successStyle.Render(model.FormatSize(m.totalCleaned)))
+	} else if len(m.removed) > 0 && len(m.failed) == 0 && len(m.untouched) == 0 {
 		// Success message with colors
 		s.WriteString(accentPink.Render("🎯 ") + headerStyle.Render(fmt.Sprintf(
-			"Successfully removed %s .venv folders!",
-			successStyle.Render(fmt.Sprintf("%d", m.cleanedCount)),
+			"Successfully removed %s virtual environments!",
+			successStyle.Render(fmt.Sprintf("%d", len(m.removed))),
 		)))
 		s.WriteString("\n\n")
 
 		// Big space freed announcement
-		s.WriteString(accentYellow.Render("💾 ") + footerStyle.Render("Total space freed: "))
-		s.WriteString(successStyle.Render(formatSize(m.totalCleaned)))
+		s.WriteString(accentYellow.Render("💾 ") + footerStyle.Render("Total space "+m.freedLabel()+": "))
+		s.WriteString(successStyle.Render(model.FormatSize(m.totalCleaned)))
 		s.WriteString("\n\n")
+		if place := cleaner.RecoveryPlace(m.cleanOpts.Tool); place != "" {
+			s.WriteString(subheaderStyle.Render("The space is freed once " + place + " is emptied."))
+			s.WriteString("\n\n")
+		}
 
 		// Celebration emojis
 		s.WriteString(accentCyan.Render("🎉 🚀 ✨ 🎊 "))
 		s.WriteString(headerStyle.Render("Your disk is cleaner!"))
 		s.WriteString(accentCyan.Render(" 🎊 ✨ 🚀 🎉"))
+	} else if len(m.failed) > 0 || len(m.untouched) > 0 {
+		// Partial success: show every side so nothing is silently lost
+		if len(m.removed) > 0 {
+			removedLabel := "Removed %s virtual environments (%s " + m.freedLabel() + "):"
+			if m.cleanOpts.DryRun {
+				removedLabel = "Would have removed %s virtual environments (%s):"
+			}
+			s.WriteString(accentCyan.Render("✅ ") + headerStyle.Render(fmt.Sprintf(
+				removedLabel,
+				successStyle.Render(fmt.Sprintf("%d", len(m.removed))),
+				successStyle.Render(model.FormatSize(m.totalCleaned)),
+			)))
+			s.WriteString("\n\n")
+			s.WriteString(renderVenvList(m.removed))
+			s.WriteString("\n")
+		}
+
+		if len(m.failed) > 0 {
+			s.WriteString(warningStyle.Render("❌ ") + headerStyle.Render(fmt.Sprintf(
+				"Failed to remove %s virtual environments:",
+				warningStyle.Render(fmt.Sprintf("%d", len(m.failed))),
+			)))
+			s.WriteString("\n\n")
+			for _, p := range m.failed {
+				// Only the artifacts failed when the venv went or was not selected
+				path := p.Item.Path()
+				if !p.Item.Selected {
+					path = "artifacts of " + p.Item.RepoPath
+				}
+				s.WriteString(fmt.Sprintf("  • %s\n", pathStyle.Render(path)))
+				s.WriteString(fmt.Sprintf("    %s\n", subheaderStyle.Render(p.Err.Error())))
+			}
+			s.WriteString("\n")
+		}
+
+		if len(m.untouched) > 0 {
+			s.WriteString(accentYellow.Render("⏸️  ") + headerStyle.Render(fmt.Sprintf(
+				"Left untouched %s virtual environments:",
+				counterStyle.Render(fmt.Sprintf("%d", len(m.untouched))),
+			)))
+			s.WriteString("\n\n")
+			s.WriteString(renderVenvList(m.untouched))
+		}
 	} else {
 		s.WriteString(accentYellow.Render("ℹ️  ") + subheaderStyle.Render("No folders were removed."))
 		s.WriteString("\n")
 	}
 
+	if errors.Is(m.cleanFailure, cleaner.ErrJournal) {
+		s.WriteString("\n\n")
+		s.WriteString(warningStyle.Render("⚠️  ") + subheaderStyle.Render("The deletion journal could not be written."))
+	}
+	if len(m.repos) > 0 && m.scanFailure != nil {
+		// Folders the scan never reached were not offered for deletion
+		s.WriteString("\n\n")
+		s.WriteString(warningStyle.Render("⚠️  ") + subheaderStyle.Render(fmt.Sprintf("Scan incomplete: %v", m.scanFailure)))
+	}
+
 	s.WriteString("\n\n")
 	s.WriteString(accentPink.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
 	s.WriteString("\n")
-	s.WriteString(helpStyle.Render("💡 Press any key to exit"))
+	if len(m.failed) > 0 {
+		s.WriteString(helpStyle.Render("💡 r: retry failed | any other key: exit"))
+	} else {
+		s.WriteString(helpStyle.Render("💡 Press any key to exit"))
+	}
 
 	return s.String()
 }
 
-func (m Model) renderRepoLine(index int, pathWidth, dateWidth int) string {
-	repo := m.repos[index]
+func (m Model) renderRepoLine(r row, index int, pathWidth, dateWidth, versionWidth int) string {
+	repo := r.info
+
+	// A row without a venv only holds artifacts, its checkbox is theirs
+	selected := repo.Selected
+	if repo.VenvPath == "" {
+		selected = repo.ArtifactsSelected
+	}
 
 	// Checkbox
 	checkbox := "[ ]"
-	if repo.Selected {
+	if selected {
 		checkbox = "[✓]"
+	} else if r.partial {
+		checkbox = "[-]"
 	}
 
 	// Cursor
@@ -389,12 +734,7 @@ func (m Model) renderRepoLine(index int, pathWidth, dateWidth int) string {
 	}
 
 	// Path (shortened if needed)
-	path := repo.RepoPath
-	if home, err := filepath.Abs(m.startPath); err == nil {
-		if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
-			path = "./" + rel
-		}
-	}
+	path := r.path
 
 	// Truncate path if too long
 	if len(path) > pathWidth {
@@ -413,8 +753,10 @@ func (m Model) renderRepoLine(index int, pathWidth, dateWidth int) string {
 		dateStr = veryOldStyle.Render(dateStr)
 	}
 
+	separator := separatorStyle.Render(" │ ")
+
 	// Size with color based on magnitude
-	sizeStr := formatSize(repo.Size)
+	sizeStr := model.FormatSize(repo.Size)
 
 	const MB = 1024 * 1024
 	const GB = 1024 * MB
@@ -429,61 +771,104 @@ func (m Model) renderRepoLine(index int, pathWidth, dateWidth int) string {
 		sizeStr = sizeHugeStyle.Render(sizeStr)
 	}
 
+	// Reclaimable size next to it, dimmed; hard links and sparse files make it smaller
+	plainSizeStr := model.FormatSize(repo.Size)
+	sizeStr += strings.Repeat(" ", max(0, 9-len(plainSizeStr))) + separator +
+		subheaderStyle.Render(model.FormatSize(repo.Reclaimable)+" reclaimable")
+	if repo.VenvPath == "" {
+		sizeStr = subheaderStyle.Render("no venv")
+	}
+
+	// Caches and build outputs of the repository, selected separately
+	if len(repo.Artifacts) > 0 {
+		artifactsBox := "[ ]"
+		if repo.ArtifactsSelected {
+			artifactsBox = "[✓]"
+		}
+		sizeStr += separator + accentYellow.Render(artifactsBox+" "+model.FormatSize(repo.ArtifactsSize)+" artifacts")
+	}
+
+	// Label environments that are not plain in-repo venvs
+	if repo.Kind != model.KindVenv {
+		sizeStr += separator + accentCyan.Render(string(repo.Kind))
+	}
+	if repo.Broken {
+		// Unusable anyway, always safe to delete
+		sizeStr += separator + warningStyle.Render("broken")
+	} else if repo.ProjectMissing {
+		// Nothing left to recreate it for
+		sizeStr += separator + warningStyle.Render("project missing")
+	} else if repo.RepoPath == "" && repo.Kind != model.KindConda {
+		// No project to recreate it from
+		sizeStr += separator + accentYellow.Render("no repo")
+	} else if !repo.Recreatable && repo.VenvPath != "" && repo.Kind != model.KindConda {
+		// No manifest or lockfile to recreate it from
+		sizeStr += separator + accentYellow.Render("not recreatable")
+	} else if repo.Worktree {
+		sizeStr += separator + accentPurple.Render("worktree")
+	}
+
 	// Pad fields for alignment (using plain strings for width calculation)
 	pathPadded := path + strings.Repeat(" ", pathWidth-len(path))
 	datePadded := plainDateStr + strings.Repeat(" ", dateWidth-len(plainDateStr))
 	// Replace plain date with colored version
 	datePadded = dateStr + strings.Repeat(" ", dateWidth-len(plainDateStr))
+	versionPadded := accentPurple.Render(repo.PythonVersion) + strings.Repeat(" ", versionWidth-len(repo.PythonVersion))
 
 	// Combine with aligned columns and colored separators
-	separator := separatorStyle.Render(" │ ")
-	line := fmt.Sprintf("%s%s %s%s%s%s%s",
+	line := fmt.Sprintf("%s%s %s%s%s%s%s%s%s",
 		cursor,
 		checkbox,
 		pathPadded,
 		separator,
 		datePadded,
 		separator,
+		versionPadded,
+		separator,
 		sizeStr,
 	)
 
 	// Highlight if selected or current
-	if repo.Selected {
+	if selected {
 		// Apply selection style to the entire line except the colored parts
 		parts := []string{
 			selectedStyle.Render(cursor + checkbox + " " + pathPadded),
 			separator,
 			datePadded,
 			separator,
+			versionPadded,
+			separator,
 			sizeStr,
 		}
 		line = strings.Join(parts, "")
 	} else if index == m.cursor {
 		// Apply cursor style to checkbox and path
-		line = cursorStyle.Render(cursor+checkbox) + " " + pathPadded + separator + datePadded + separator + sizeStr
+		line = cursorStyle.Render(cursor+checkbox) + " " + pathPadded + separator + datePadded + separator + versionPadded + separator + sizeStr
+	} else if repo.Broken {
+		// Make broken venvs stand out
+		line = warningStyle.Render(cursor+checkbox+" "+pathPadded) + separator + datePadded + separator + versionPadded + separator + sizeStr
 	}
 
 	return line
 }
 
-func (m Model) getVisibleRange() (int, int) {
-	// For now, show all repos. Could add pagination later.
-	return 0, len(m.repos)
+func (m Model) getVisibleRange(rows int) (int, int) {
+	// For now, show all rows. Could add pagination later.
+	return 0, rows
 }
 
 // calculateColumnWidths calculates the maximum width needed for each column
-func (m Model) calculateColumnWidths() (pathWidth, dateWidth int) {
-	pathWidth = 20  // minimum width
-	dateWidth = 15  // minimum width
+func (m Model) calculateColumnWidths() (pathWidth, dateWidth, versionWidth int) {
+	pathWidth = 20   // minimum width
+	dateWidth = 15   // minimum width
+	versionWidth = 6 // minimum width
+
+	for _, r := range m.rows() {
+		repo := r.info
 
-	for _, repo := range m.repos {
 		// Calculate path width
-		path := repo.RepoPath
-		if rel, err := filepath.Rel(m.startPath, path); err == nil && !strings.HasPrefix(rel, "..") {
-			path = "./" + rel
-		}
-		if len(path) > pathWidth {
-			pathWidth = len(path)
+		if len(r.path) > pathWidth {
+			pathWidth = len(r.path)
 		}
 
 		// Calculate date width
@@ -491,6 +876,11 @@ func (m Model) calculateColumnWidths() (pathWidth, dateWidth int) {
 		if len(dateStr) > dateWidth {
 			dateWidth = len(dateStr)
 		}
+
+		// Calculate Python version width
+		if len(repo.PythonVersion) > versionWidth {
+			versionWidth = len(repo.PythonVersion)
+		}
 	}
 
 	// Cap the path width to avoid overly long lines
@@ -498,21 +888,20 @@ func (m Model) calculateColumnWidths() (pathWidth, dateWidth int) {
 		pathWidth = 60
 	}
 
-	return pathWidth, dateWidth
+	return pathWidth, dateWidth, versionWidth
 }
 
-// formatSize converts bytes to human-readable format
-func formatSize(bytes int64) string {
-	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%d B", bytes)
-	}
-	div, exp := int64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
+// formatCount formats a count with thousands separators
+func formatCount(n int) string {
+	digits := fmt.Sprintf("%d", n)
+	var s strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			s.WriteByte(',')
+		}
+		s.WriteRune(d)
 	}
-	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+	return s.String()
 }
 
 // formatDate formats a time in a human-readable way